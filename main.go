@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 	"squash-helper/client"
+	"squash-helper/mock"
 	"squash-helper/server"
 )
 
@@ -20,9 +21,12 @@ func main() {
 		mod = args[0]
 	}
 
-	if mod == "client" {
+	switch mod {
+	case "client":
 		client.Run()
-	} else {
+	case "mock":
+		mock.Run()
+	default:
 		server.Run()
 	}
 }
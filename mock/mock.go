@@ -0,0 +1,372 @@
+// Package mock 은 auc.or.kr 의 로그인, 강습 목록, 신청내역 화면을 흉내 내는 모의 사이트입니다.
+// 신청 기간이 아닐 때도 자동화 흐름을 끝까지 연습할 수 있도록 선택자와 대화상자를 실제 사이트와 같게 맞춥니다.
+package mock
+
+import (
+	"crypto/rand"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed web/*.html
+var mockFS embed.FS
+
+var pages = template.Must(template.ParseFS(mockFS, "web/*.html"))
+
+const sessionCookieName = "mock-session"
+
+var kst = time.FixedZone("KST", 9*60*60)
+
+// mockLesson 은 강습 목록의 한 줄입니다. insertOrderSeq 인자 순서대로 필드를 둡니다.
+type mockLesson struct {
+	GroupSeq     string
+	LessonSeq    string
+	EntranceType string
+	EntranceCode string
+	DayPattern   string
+	TimeRange    string
+	Sport        string
+	Instructor   string
+	Area         string
+	Period       string
+	Registered   int
+	Capacity     int
+}
+
+func (l *mockLesson) key() string {
+	return l.GroupSeq + "-" + l.LessonSeq
+}
+
+// mockRegistration 은 신청내역 한 건입니다.
+type mockRegistration struct {
+	ID       string
+	Lesson   *mockLesson
+	Status   string
+	Deadline string
+}
+
+type mockSite struct {
+	user     string
+	password string
+	// 강습 목록에 처음 들어올 때 대기열(#waitPage)을 보여줄 시간
+	waitFor time.Duration
+	// 이 시각 전에는 신청하면 "신청 기간이 아닙니다"라고 답합니다.
+	openAt time.Time
+
+	mu            sync.Mutex
+	sessions      map[string]bool
+	lessons       []*mockLesson
+	registrations []*mockRegistration
+}
+
+func newMockSite() *mockSite {
+	m := &mockSite{
+		user:     envOr("MOCK_USER", "mock"),
+		password: envOr("MOCK_PASSWORD", "mock"),
+		waitFor:  3 * time.Second,
+		sessions: map[string]bool{},
+		lessons:  defaultLessons(),
+	}
+
+	if v := strings.TrimSpace(os.Getenv("MOCK_WAIT_SECONDS")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Fatalf("MOCK_WAIT_SECONDS는 0 이상의 정수여야 합니다: %q", v)
+		}
+		m.waitFor = time.Duration(n) * time.Second
+	}
+	if v := strings.TrimSpace(os.Getenv("MOCK_OPEN_AT")); v != "" {
+		t, err := time.ParseInLocation("2006-01-02 15:04:05", v, kst)
+		if err != nil {
+			log.Fatalf("MOCK_OPEN_AT 형식이 올바르지 않습니다 (예: 2026-10-25 10:00:00): %v", err)
+		}
+		m.openAt = t
+	}
+
+	return m
+}
+
+func defaultLessons() []*mockLesson {
+	return []*mockLesson{
+		{"11", "201", "주2일(월,수)", "01", "주2일(월,수)", "20:00 - 21:00", "스쿼시", "김민수", "호계스쿼시", "2026.11.01 ~ 2026.11.30", 8, 10},
+		{"11", "202", "주2일(월,수)", "01", "주2일(월,수)", "21:00 - 22:00", "스쿼시", "김민수", "호계스쿼시", "2026.11.01 ~ 2026.11.30", 10, 10},
+		{"11", "211", "주2일(화,목)", "02", "주2일(화,목)", "20:00 - 21:00", "스쿼시", "이지은", "호계스쿼시", "2026.11.01 ~ 2026.11.30", 9, 10},
+		{"11", "212", "주2일(화,목)", "02", "주2일(화,목)", "21:00 - 22:00", "스쿼시", "이지은", "호계스쿼시", "2026.11.01 ~ 2026.11.30", 4, 10},
+		{"11", "221", "화목(강습)", "03", "화목(강습)", "20:00 - 21:00", "스쿼시", "박준호", "호계스쿼시", "2026.11.01 ~ 2026.11.30", 5, 6},
+		{"12", "218", "주2일(화,목)", "03", "주2일(화,목)", "11:00 - 12:30", "배드민턴", "임미정", "호계배드민턴", "2026.11.01 ~ 2026.11.30", 12, 20},
+	}
+}
+
+func envOr(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
+
+func newToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("토큰 생성 실패: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (m *mockSite) loggedIn(r *http.Request) bool {
+	cookie, err := r.Cookie(sessionCookieName)
+	if err != nil {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[cookie.Value]
+}
+
+func render(w http.ResponseWriter, name string, data any) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pages.ExecuteTemplate(w, name, data); err != nil {
+		log.Printf("%s 렌더링 실패: %v", name, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("응답 인코딩 실패: %v", err)
+	}
+}
+
+func (m *mockSite) Main(w http.ResponseWriter, r *http.Request) {
+	render(w, "main.html", struct{ LoggedIn bool }{m.loggedIn(r)})
+}
+
+// SignIn 은 auc.or.kr 의 로그인 선택 화면입니다. 통합 로그인 버튼으로 SSO 화면에 갑니다.
+func (m *mockSite) SignIn(w http.ResponseWriter, r *http.Request) {
+	render(w, "sign_in.html", nil)
+}
+
+// SSOLogin 은 통합 로그인 화면입니다. 화면을 열면 확인 대화상자 두 개가 차례로 뜹니다.
+// 실패하면 같은 주소에 머무르고, 성공하면 메인 페이지로 돌아갑니다.
+func (m *mockSite) SSOLogin(w http.ResponseWriter, r *http.Request) {
+	data := struct{ Error string }{}
+
+	if r.Method == http.MethodPost {
+		if r.FormValue("login_id") == m.user && r.FormValue("login_pwd") == m.password {
+			token := newToken()
+			m.mu.Lock()
+			m.sessions[token] = true
+			m.mu.Unlock()
+
+			http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: token, Path: "/", HttpOnly: true})
+			http.Redirect(w, r, "/hogye/main/view", http.StatusFound)
+			return
+		}
+		data.Error = "아이디 또는 비밀번호가 일치하지 않습니다."
+	}
+
+	render(w, "sso_login.html", data)
+}
+
+func (m *mockSite) Logout(w http.ResponseWriter, r *http.Request) {
+	if cookie, err := r.Cookie(sessionCookieName); err == nil {
+		m.mu.Lock()
+		delete(m.sessions, cookie.Value)
+		m.mu.Unlock()
+	}
+	http.SetCookie(w, &http.Cookie{Name: sessionCookieName, Value: "", Path: "/", MaxAge: -1})
+	http.Redirect(w, r, "/hogye/main/view", http.StatusFound)
+}
+
+type lessonRow struct {
+	*mockLesson
+	Full bool
+}
+
+// LessonList 는 강습 목록입니다. 구분/과정을 바꾸면 쿼리와 함께 다시 불러옵니다.
+// 구분을 고르지 않고 처음 들어오면 대기열 오버레이를 잠시 보여줍니다.
+func (m *mockSite) LessonList(w http.ResponseWriter, r *http.Request) {
+	area := r.URL.Query().Get("areaGbn")
+	entrance := r.URL.Query().Get("entranceType")
+
+	m.mu.Lock()
+	var (
+		areas, entrances []string
+		rows             []lessonRow
+		seenArea         = map[string]bool{}
+		seenEntrance     = map[string]bool{}
+	)
+	for _, l := range m.lessons {
+		if !seenArea[l.Area] {
+			seenArea[l.Area] = true
+			areas = append(areas, l.Area)
+		}
+		if area != "" && l.Area != area {
+			continue
+		}
+		if !seenEntrance[l.EntranceType] {
+			seenEntrance[l.EntranceType] = true
+			entrances = append(entrances, l.EntranceType)
+		}
+		if entrance != "" && l.EntranceType != entrance {
+			continue
+		}
+		rows = append(rows, lessonRow{mockLesson: l, Full: l.Registered >= l.Capacity})
+	}
+	m.mu.Unlock()
+
+	waitSeconds := 0
+	if area == "" && entrance == "" {
+		waitSeconds = int(m.waitFor / time.Second)
+	}
+
+	render(w, "lesson_list.html", struct {
+		LoggedIn    bool
+		Area        string
+		Entrance    string
+		Areas       []string
+		Entrances   []string
+		Lessons     []lessonRow
+		WaitSeconds int
+	}{m.loggedIn(r), area, entrance, areas, entrances, rows, waitSeconds})
+}
+
+// Apply 는 insertOrderSeq 가 부르는 신청 처리입니다. 결과 문구는 alert 로 보여줍니다.
+func (m *mockSite) Apply(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+
+	type result struct {
+		Message  string `json:"message"`
+		Redirect string `json:"redirect,omitempty"`
+	}
+
+	if !m.loggedIn(r) {
+		writeJSON(w, result{Message: "로그인 후 이용해주세요.", Redirect: "/sign/in/base/user"})
+		return
+	}
+	if !m.openAt.IsZero() && time.Now().Before(m.openAt) {
+		writeJSON(w, result{Message: fmt.Sprintf("신청 기간이 아닙니다. (%s 오픈)", m.openAt.Format("2006-01-02 15:04:05"))})
+		return
+	}
+
+	key := r.FormValue("groupSeq") + "-" + r.FormValue("lessonSeq")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var target *mockLesson
+	for _, l := range m.lessons {
+		if l.key() == key {
+			target = l
+			break
+		}
+	}
+	if target == nil {
+		writeJSON(w, result{Message: "존재하지 않는 강습입니다."})
+		return
+	}
+	for _, reg := range m.registrations {
+		if reg.Lesson == target && reg.Status != "취소" {
+			writeJSON(w, result{Message: "이미 신청한 강습입니다."})
+			return
+		}
+	}
+	if target.Registered >= target.Capacity {
+		writeJSON(w, result{Message: "정원이 마감되었습니다."})
+		return
+	}
+
+	target.Registered++
+	m.registrations = append(m.registrations, &mockRegistration{
+		ID:       newToken()[:8],
+		Lesson:   target,
+		Status:   "결제대기",
+		Deadline: time.Now().In(kst).Add(24 * time.Hour).Format("2006.01.02 15:04"),
+	})
+	writeJSON(w, result{Message: "신청이 완료되었습니다. 결제기한 내에 결제해주세요."})
+}
+
+// Registrations 는 신청내역입니다. 로그인하지 않았으면 로그인 화면으로 보냅니다.
+func (m *mockSite) Registrations(w http.ResponseWriter, r *http.Request) {
+	if !m.loggedIn(r) {
+		http.Redirect(w, r, "/sign/in/base/user", http.StatusFound)
+		return
+	}
+
+	m.mu.Lock()
+	regs := append([]*mockRegistration{}, m.registrations...)
+	m.mu.Unlock()
+
+	render(w, "registrations.html", struct{ Registrations []*mockRegistration }{regs})
+}
+
+func (m *mockSite) CancelRegistration(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST only", http.StatusMethodNotAllowed)
+		return
+	}
+	if !m.loggedIn(r) {
+		writeJSON(w, map[string]string{"message": "로그인 후 이용해주세요."})
+		return
+	}
+
+	id := r.FormValue("id")
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, reg := range m.registrations {
+		if reg.ID == id && reg.Status != "취소" {
+			reg.Status = "취소"
+			reg.Lesson.Registered--
+			writeJSON(w, map[string]string{"message": "신청이 취소되었습니다."})
+			return
+		}
+	}
+	writeJSON(w, map[string]string{"message": "취소할 수 없는 신청입니다."})
+}
+
+// Handler 는 새 모의 사이트의 핸들러를 돌려줍니다. 상태는 핸들러마다 따로 가집니다.
+func Handler() http.Handler {
+	return newMockSite().handler()
+}
+
+// Run 은 모의 사이트를 MOCK_ADDR(기본 :9090)에서 실행합니다.
+func Run() {
+	m := newMockSite()
+	addr := envOr("MOCK_ADDR", ":9090")
+	log.Printf("모의 사이트 실행: %s (아이디 %s)", addr, m.user)
+	log.Fatal(http.ListenAndServe(addr, m.handler()))
+}
+
+func (m *mockSite) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hogye/main/view", m.Main)
+	mux.HandleFunc("/sign/in/base/user", m.SignIn)
+	mux.HandleFunc("/sign/out", m.Logout)
+	mux.HandleFunc("/sso/login", m.SSOLogin)
+	mux.HandleFunc("/reservation/program/lesson/list", m.LessonList)
+	mux.HandleFunc("/reservation/program/lesson/apply", m.Apply)
+	mux.HandleFunc("/mypage/lesson/list", m.Registrations)
+	mux.HandleFunc("/mypage/lesson/cancel", m.CancelRegistration)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, "/hogye/main/view", http.StatusFound)
+	})
+	return mux
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"crypto/rand"
 	"embed"
 	"encoding/base64"
@@ -10,6 +11,7 @@ import (
 	"io/fs"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -27,13 +29,40 @@ type userSession struct {
 	createdAt  time.Time
 	lastActive time.Time
 
-	statusMu      sync.Mutex
-	statusCh      chan statusEvent
-	lastStatus    statusEvent
-	hasLastStatus bool
+	// site 는 세션을 만들 때 고른 사이트 어댑터입니다. 세션이 끝날 때까지 바뀌지 않습니다.
+	site siteAdapter
+
+	// busy 는 브라우저 작업 잠금입니다. 한 번에 하나의 작업만 페이지를 다룹니다.
+	busy     chan struct{}
+	opMu     sync.Mutex
+	opCancel context.CancelFunc
+
+	status *statusBroadcaster
+
+	scheduleMu sync.Mutex
+	schedule   *applySchedule
+
+	monitorMu sync.Mutex
+	monitor   *seatMonitor
+
+	castMu sync.Mutex
+	cast   *screencast
+
+	snapshotMu sync.Mutex
+	snapshots  []*pageSnapshot
+
+	harMu sync.Mutex
+	har   *harRecorder
+
+	dialogMu sync.Mutex
+	dialogs  *dialogHandler
+
+	settingsMu sync.Mutex
+	waitMode   string
 }
 
 type statusEvent struct {
+	ID      uint64    `json:"id"`
 	Level   string    `json:"level"`
 	Message string    `json:"message"`
 	At      time.Time `json:"at"`
@@ -49,6 +78,16 @@ var (
 	sessions  = make(map[string]*userSession)
 )
 
+func newUserSession(browser *rod.Browser, page *rod.Page, site siteAdapter) *userSession {
+	return &userSession{
+		browser: browser,
+		page:    page,
+		site:    site,
+		busy:    make(chan struct{}, 1),
+		status:  newStatusBroadcaster(statusConfig),
+	}
+}
+
 func (s *userSession) pushStatus(level, message string) {
 	if s == nil {
 		return
@@ -70,16 +109,7 @@ func (s *userSession) pushStatus(level, message string) {
 		At:      time.Now(),
 	}
 
-	s.statusMu.Lock()
-	s.lastStatus = ev
-	s.hasLastStatus = true
-	if s.statusCh != nil {
-		select {
-		case s.statusCh <- ev:
-		default:
-		}
-	}
-	s.statusMu.Unlock()
+	s.status.publish(ev)
 }
 
 func (s *userSession) pushInfo(message string) {
@@ -90,47 +120,18 @@ func (s *userSession) pushError(message string) {
 	s.pushStatus("error", message)
 }
 
-func (s *userSession) subscribeStatus() (chan statusEvent, []statusEvent, func()) {
+func (s *userSession) subscribeStatus(after uint64, resume bool) (<-chan statusEvent, []statusEvent, func()) {
 	if s == nil {
 		return nil, nil, func() {}
 	}
-
-	s.statusMu.Lock()
-	prev := s.statusCh
-	if prev != nil {
-		close(prev)
-	}
-	ch := make(chan statusEvent, 16)
-	s.statusCh = ch
-	var history []statusEvent
-	if s.hasLastStatus {
-		history = append(history, s.lastStatus)
-	}
-	s.statusMu.Unlock()
-
-	cleanup := func() {
-		s.statusMu.Lock()
-		if s.statusCh == ch {
-			close(ch)
-			s.statusCh = nil
-		}
-		s.statusMu.Unlock()
-	}
-
-	return ch, history, cleanup
+	return s.status.subscribe(after, resume)
 }
 
 func (s *userSession) closeStatusChannel() {
 	if s == nil {
 		return
 	}
-
-	s.statusMu.Lock()
-	if s.statusCh != nil {
-		close(s.statusCh)
-		s.statusCh = nil
-	}
-	s.statusMu.Unlock()
+	s.status.close()
 }
 
 func Run() {
@@ -140,19 +141,59 @@ func Run() {
 		log.Fatal(err)
 	}
 
+	if err := loadProfiles(); err != nil {
+		log.Fatal(err)
+	}
+	if err := loadDialogRules(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := loadSelectors(); err != nil {
+		log.Fatal(err)
+	}
+	if err := loadWorkflows(); err != nil {
+		log.Fatal(err)
+	}
+	go watchSelectors()
+	go scheduleCanary()
+
 	mux := http.NewServeMux()
+	mux.HandleFunc("/sites", Sites)
 	mux.HandleFunc("/launch", Launch)
 	mux.HandleFunc("/login", Login)
+	mux.HandleFunc("/login/forget", ForgetLogin)
 	mux.HandleFunc("/move", Move)
 	mux.HandleFunc("/action", Action)
+	mux.HandleFunc("/workflows", Workflows)
+	mux.HandleFunc("/profiles", Profiles)
+	mux.HandleFunc("/lessons", Lessons)
+	mux.HandleFunc("/lessons/apply", ApplyLesson)
+	mux.HandleFunc("/monitor", Monitor)
+	mux.HandleFunc("/schedule", Schedule)
+	mux.HandleFunc("/time", SiteTime)
 	mux.HandleFunc("/screenshot", Screenshot)
+	mux.HandleFunc("/screencast", Screencast)
+	mux.HandleFunc("/input", Input)
+	mux.HandleFunc("/snapshot", Snapshot)
+	mux.HandleFunc("/snapshots", Snapshots)
+	mux.HandleFunc("/har", Har)
+	mux.HandleFunc("/dialogs", Dialogs)
+	mux.HandleFunc("/selectors", Selectors)
+	mux.HandleFunc("/canary", Canary)
+	mux.HandleFunc("/registrations", Registrations)
+	mux.HandleFunc("/registrations/cancel", CancelRegistration)
 	mux.HandleFunc("/refresh", Refresh)
 	mux.HandleFunc("/close", Close)
+	mux.HandleFunc("/cancel", Cancel)
 	mux.HandleFunc("/remove-waiting", RemoveWaiting)
+	mux.HandleFunc("/waiting", Waiting)
 	mux.HandleFunc("/status/stream", StatusStream)
+	mux.HandleFunc("/status/history", StatusHistory)
 	mux.Handle("/", http.FileServer(http.FS(sub)))
 
 	go startSessionReaper()
+	go pool.run()
+	clockFor(defaultSite)
 
 	// 서버 실행
 	fmt.Println("서버 실행 중... http://localhost:8080")
@@ -201,10 +242,18 @@ func cleanupSession(sessionID string) {
 		return
 	}
 
+	session.cancelSchedule()
+	session.stopMonitor()
+	session.stopScreencast()
+	session.stopRecording()
+	session.stopDialogs()
+	session.cancelOperation()
+
+	// 세션의 browser 는 공유 브라우저의 시크릿 컨텍스트이므로 Close 는 해당 컨텍스트만 정리합니다.
 	session.mu.Lock()
 	if session.browser != nil {
 		if err := session.browser.Close(); err != nil {
-			log.Printf("세션 %s 브라우저 종료 실패: %v", sessionID, err)
+			log.Printf("세션 %s 브라우저 컨텍스트 종료 실패: %v", sessionID, err)
 		}
 	}
 	session.browser = nil
@@ -275,7 +324,8 @@ func startSessionReaper() {
 			lastActive := session.lastActive
 			session.mu.Unlock()
 
-			if now.Sub(lastActive) > sessionTTL {
+			// 예약 신청이나 좌석 감시가 걸린 세션은 유지합니다. 좌석 감시는 MONITOR_MAX_DURATION 이 지나면 스스로 끝납니다.
+			if now.Sub(lastActive) > sessionTTL && !session.hasSchedule() && !session.hasMonitor() {
 				expired = append(expired, id)
 			}
 		}
@@ -285,6 +335,9 @@ func startSessionReaper() {
 			log.Printf("세션 %s이(가) 비활성 상태로 만료되어 종료합니다.", id)
 			cleanupSession(id)
 		}
+
+		// 대기 페이지는 세션에 등록되지 않으므로 따로 정리합니다.
+		pool.reap(now)
 	}
 }
 
@@ -318,48 +371,48 @@ func Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
-
-	page := session.page
-
-	// 아이디 입력
-	session.pushInfo("아이디 입력 필드를 찾습니다.")
-	login_id := page.MustElement("#login_id")
-	login_id.MustInput(payload.ID)
-	session.pushInfo("아이디 입력을 완료했습니다.")
-	time.Sleep(1 * time.Second)
-
-	// 비밀번호 입력
-	session.pushInfo("비밀번호 입력 필드를 찾습니다.")
-	login_password := page.MustElement("#login_pwd")
-	login_password.MustInput(payload.Password)
-	session.pushInfo("비밀번호 입력을 완료했습니다.")
-	time.Sleep(1 * time.Second)
-
-	// 로그인 버튼 클릭
-	session.pushInfo("로그인 버튼을 클릭합니다.")
-	buttons := page.MustElements("button")
-	for _, button := range buttons {
-		if button.MustText() == "로그인" {
-			button.MustClick()
-			session.pushInfo("로그인 버튼을 클릭했습니다.")
-			break
-		}
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
 	}
+	defer done()
+
+	if err := session.runSiteSteps(page, session.site.SubmitLogin(payload.ID, payload.Password), nil); err != nil {
+		session.failStep(w, err)
+		return
+	}
+
 	// 페이지 진입 대기
-	session.pushInfo("로그인 결과를 확인 중입니다.")
-	page.MustWaitLoad()
-	time.Sleep(3 * time.Second)
+	var url string
+	if err := session.step("로그인 결과를 확인 중입니다.", func() error {
+		if err := waitLoad(page); err != nil {
+			return err
+		}
+		if err := pause(page, 3*time.Second); err != nil {
+			return err
+		}
 
-	url := page.MustInfo().URL
-	if strings.HasPrefix(url, "https://newsso.anyang.go.kr/") {
+		info, err := page.Info()
+		if err != nil {
+			return fmt.Errorf("페이지 정보 조회 실패: %w", err)
+		}
+		url = info.URL
+		return nil
+	}); err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	if session.site.LoginFailed(url) {
 		session.pushError("로그인에 실패했습니다. 아이디와 비밀번호를 확인해 주세요.")
 		http.Error(w, "로그인 실패하였습니다. 아이디와 비밀번호를 확인해주세요.", http.StatusForbidden)
 		return
 	}
 
 	session.pushInfo("로그인에 성공했습니다.")
+	session.setDialogStage("")
+	session.saveLogin(w, r, page)
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("로그인 완료"))
 }
@@ -370,16 +423,20 @@ func Move(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
 
-	page := session.page
-	session.pushInfo("강습 신청 페이지로 이동합니다.")
-	page.MustNavigate("https://www.auc.or.kr/reservation/program/lesson/list")
-	session.pushInfo("강습 신청 페이지를 불러오는 중입니다.")
-	// 페이지 진입 대기
-	page.MustWaitLoad()
-	removeWaitPage(page)
+	if err := session.step("강습 신청 페이지로 이동합니다.", func() error {
+		return navigate(page, session.site.LessonListURL())
+	}); err != nil {
+		session.failStep(w, err)
+		return
+	}
+	session.handleWaitPage(page)
 	session.pushInfo("강습 신청 페이지 진입을 완료했습니다.")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("강습 신청 페이지 진입 완료"))
@@ -391,126 +448,39 @@ func Action(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
-
-	page := session.page
-
-	code := r.URL.Query().Get("code")
-	if code != "" {
-		session.pushInfo(fmt.Sprintf("요청 코드 %s 작업을 시작합니다.", code))
-	}
-
-	switch code {
-	case "1":
-		session.pushInfo("강습 구분을 선택합니다.")
-		if forceSelect(page, "#areaGbn", "호계스쿼시") {
-			// 페이지 진입 대기
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 구분 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 구분 선택 완료"))
-		} else {
-			session.pushError("강습 구분 선택에 실패했습니다.")
-			http.Error(w, "강습 구분 선택 실패", http.StatusNotFound)
-		}
-	case "2":
-		session.pushInfo("강습 과정을 선택합니다.")
-		if forceSelect(page, "#entranceType", "주2일(월,수)") {
-			// 페이지 진입 대기
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 과정 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 과정 선택 완료"))
-		} else {
-			session.pushError("강습 과정 선택에 실패했습니다.")
-			http.Error(w, "강습 과정 선택 실패", http.StatusNotFound)
-		}
-	case "3":
-		session.pushInfo("강습 과정을 선택합니다.")
-		if clickLessonTime(page, "주2일(월,수)", "20:00 - 21:00") {
-			page.MustWaitLoad()
-			session.pushInfo("강습 시간 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 시간 선택 완료"))
-		} else {
-			session.pushError("조건에 맞는 강습 시간을 찾지 못했습니다.")
-			http.Error(w, "조건에 맞는 강습 시간 버튼을 찾지 못했습니다.", http.StatusNotFound)
-		}
-	case "4":
-		session.pushInfo("강습 과정을 선택합니다.")
-		if forceSelect(page, "#entranceType", "주2일(화,목)") {
-			// 페이지 진입 대기
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 과정 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 과정 선택 완료"))
-		} else {
-			session.pushError("강습 과정 선택에 실패했습니다.")
-			http.Error(w, "강습 과정 선택 실패", http.StatusNotFound)
-		}
-	case "5":
-		session.pushInfo("조건에 맞는 강습 시간을 찾는 중입니다.")
-		if clickLessonTime(page, "주2일(화,목)", "20:00 - 21:00") {
-			page.MustWaitLoad()
-			session.pushInfo("강습 시간 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 시간 선택 완료"))
-		} else {
-			session.pushError("조건에 맞는 강습 시간을 찾지 못했습니다.")
-			http.Error(w, "조건에 맞는 강습 시간 버튼을 찾지 못했습니다.", http.StatusNotFound)
-		}
-	case "9":
-		session.pushInfo("강습 목록 페이지로 이동합니다.")
-		page.MustNavigate("https://www.auc.or.kr/reservation/program/lesson/list")
-		// 페이지 진입 대기
-		page.MustWaitLoad()
-		session.pushInfo("강습 목록 페이지 로딩이 완료되었습니다.")
-		time.Sleep(500 * time.Millisecond)
-
-		session.pushInfo("강습 구분을 선택합니다.")
-		if forceSelect(page, "#areaGbn", "호계스쿼시") {
-			// 페이지 진입 대기
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 구분 선택을 완료했습니다.")
-			time.Sleep(500 * time.Millisecond)
-		} else {
-			session.pushError("강습 구분 선택에 실패했습니다.")
-			http.Error(w, "강습 구분 선택 실패", http.StatusNotFound)
-			return
-		}
+	name := r.URL.Query().Get("profile")
+	profile, ok := findSiteProfile(name, session.site)
+	if !ok {
+		http.Error(w, "알 수 없는 대상 프로필입니다.", http.StatusBadRequest)
+		return
+	}
 
-		session.pushInfo("강습 과정을 선택합니다.")
-		if forceSelect(page, "#entranceType", "화목(강습)") {
-			// 페이지 진입 대기
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 과정 선택을 완료했습니다.")
-			time.Sleep(500 * time.Millisecond)
-		} else {
-			session.pushError("강습 과정 선택에 실패했습니다.")
-			http.Error(w, "강습 과정 선택 실패", http.StatusNotFound)
-			return
-		}
+	wf, ok := findSiteWorkflow(r.URL.Query().Get("step"), session.site)
+	if !ok {
+		http.Error(w, "알 수 없는 작업 단계입니다.", http.StatusBadRequest)
+		return
+	}
 
-		session.pushInfo("조건에 맞는 정기 강습 시간을 찾는 중입니다.")
-		if clickLessonTime(page, "화목(강습)", "20:00 - 21:00") {
-			page.MustWaitLoad()
-			removeWaitPage(page)
-			session.pushInfo("강습 시간 선택을 완료했습니다.")
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("강습 시간 선택 완료"))
-		} else {
-			session.pushError("조건에 맞는 강습 시간을 찾지 못했습니다.")
-			http.Error(w, "조건에 맞는 강습 시간 버튼을 찾지 못했습니다.", http.StatusNotFound)
-		}
-	default:
-		http.Error(w, "알 수 없는 작업 코드입니다.", http.StatusBadRequest)
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	session.pushInfo(fmt.Sprintf("[%s] %s 작업을 시작합니다.", profile.Label, wf.Label))
+
+	report, err := session.runWorkflow(page, wf, profile)
+	if err != nil {
+		log.Printf("작업 흐름 %s 실패: %v", wf.Name, err)
+		session.snapshotOnFailure(err)
+		session.pushError(fmt.Sprintf("[%s] %d/%d단계에서 멈췄습니다: %v", wf.Label, report.Failed.Index, len(wf.Steps), err))
+		writeWorkflowReport(w, stepStatus(err), report)
+		return
 	}
+
+	session.pushInfo(fmt.Sprintf("[%s] %s", wf.Label, wf.Done))
+	writeWorkflowReport(w, http.StatusOK, report)
 }
 
 func StatusStream(w http.ResponseWriter, r *http.Request) {
@@ -530,7 +500,15 @@ func StatusStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.Header().Set("X-Accel-Buffering", "no")
 
-	ch, history, cleanup := session.subscribeStatus()
+	// 브라우저의 자동 재연결은 Last-Event-ID 헤더를, 화면의 수동 재연결은 lastEventId 쿼리를 보냅니다.
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+	after, err := strconv.ParseUint(strings.TrimSpace(lastEventID), 10, 64)
+	resume := err == nil
+
+	ch, history, cleanup := session.subscribeStatus(after, resume)
 	defer cleanup()
 
 	sendEvent := func(ev statusEvent) bool {
@@ -540,7 +518,7 @@ func StatusStream(w http.ResponseWriter, r *http.Request) {
 			return true
 		}
 
-		if _, err := fmt.Fprintf(w, "data: %s\n\n", payload); err != nil {
+		if _, err := fmt.Fprintf(w, "id: %d\ndata: %s\n\n", ev.ID, payload); err != nil {
 			return false
 		}
 
@@ -554,7 +532,7 @@ func StatusStream(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if len(history) == 0 {
+	if len(history) == 0 && !resume {
 		session.pushInfo("상태 모니터링이 연결되었습니다.")
 	}
 
@@ -574,6 +552,19 @@ func StatusStream(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// StatusHistory 는 세션에 보관된 상태 기록 전체를 JSON 으로 돌려줍니다.
+func StatusHistory(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(session.status.history()); err != nil {
+		log.Printf("상태 기록 응답 인코딩 실패: %v", err)
+	}
+}
+
 func Screenshot(w http.ResponseWriter, r *http.Request) {
 	session, _, ok := requireSession(w, r)
 	if !ok {
@@ -582,15 +573,14 @@ func Screenshot(w http.ResponseWriter, r *http.Request) {
 
 	session.pushInfo("스크린샷을 요청했습니다.")
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
-
-	if session.page == nil {
-		http.Error(w, "활성화된 페이지가 없습니다.", http.StatusBadRequest)
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
 		return
 	}
+	defer done()
 
-	data, err := session.page.Screenshot(true, nil)
+	data, err := page.Screenshot(true, nil)
 	if err != nil {
 		log.Printf("세션 화면 캡처 실패: %v", err)
 		session.pushError("스크린샷 캡처에 실패했습니다.")
@@ -615,8 +605,8 @@ func Screenshot(w http.ResponseWriter, r *http.Request) {
 
 // forceSelect 은 오버레이와 상관없이 <select>를 강제로 선택하고
 // input/change 이벤트까지 발생시킵니다.
-func forceSelect(page *rod.Page, sel string, want string) bool {
-	return page.MustEval(`(sel, want) => {
+func forceSelect(page *rod.Page, sel string, want string) (bool, error) {
+	res, err := page.Eval(`(sel, want) => {
 		const s = document.querySelector(sel);
 		if (!s) return false;
 
@@ -640,20 +630,33 @@ func forceSelect(page *rod.Page, sel string, want string) bool {
 		}
 
 		return false;
-	}`, sel, want).Bool()
+	}`, sel, want)
+	if err != nil {
+		return false, err
+	}
+	return res.Value.Bool(), nil
 }
 
-func clickLessonTime(page *rod.Page, lessonType, timeRange string) bool {
-	btns := page.MustElements("a.common_btn.regist")
+func clickLessonTime(page *rod.Page, spec lessonButtonSpec, lessonType, timeRange string) (bool, error) {
+	btns, err := page.Elements(spec.Selector)
+	if err != nil {
+		return false, fmt.Errorf("신청 버튼 목록 조회 실패: %w", err)
+	}
 	for _, btn := range btns {
-		html := btn.MustProperty("outerHTML").String()
+		prop, err := btn.Property("outerHTML")
+		if err != nil {
+			return false, fmt.Errorf("신청 버튼 읽기 실패: %w", err)
+		}
+		html := prop.String()
 		if strings.Contains(html, lessonType) &&
 			strings.Contains(html, timeRange) &&
-			strings.Contains(html, "신청") {
-			btn.MustEval(`() => this.click()`)
-			return true
+			strings.Contains(html, spec.ApplyText) {
+			if _, err := btn.Eval(`() => this.click()`); err != nil {
+				return false, fmt.Errorf("신청 버튼 클릭 실패: %w", err)
+			}
+			return true, nil
 		}
 	}
 
-	return false
+	return false, nil
 }
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/stealth"
+)
+
+// browserError 는 사용자에게 보여줄 메시지와 원인을 함께 담습니다.
+type browserError struct {
+	userMessage string
+	err         error
+}
+
+func (e *browserError) Error() string {
+	return fmt.Sprintf("%s: %v", e.userMessage, e.err)
+}
+
+func (e *browserError) Unwrap() error {
+	return e.err
+}
+
+var (
+	sharedBrowserMu sync.Mutex
+	sharedBrowser   *rod.Browser
+)
+
+// acquireBrowser 는 서버 전체가 공유하는 Chromium 프로세스를 돌려줍니다.
+// 처음 호출되었거나 기존 프로세스와의 연결이 끊겼으면 새로 실행합니다.
+func acquireBrowser() (*rod.Browser, error) {
+	sharedBrowserMu.Lock()
+	defer sharedBrowserMu.Unlock()
+
+	if sharedBrowser != nil {
+		if _, err := (proto.BrowserGetVersion{}).Call(sharedBrowser); err == nil {
+			return sharedBrowser, nil
+		}
+		log.Printf("공유 브라우저 연결이 끊겨 다시 실행합니다.")
+		_ = sharedBrowser.Close()
+		sharedBrowser = nil
+	}
+
+	browser, err := launchBrowser()
+	if err != nil {
+		return nil, err
+	}
+	sharedBrowser = browser
+	return browser, nil
+}
+
+func launchBrowser() (*rod.Browser, error) {
+	browser, _, err := startBrowser()
+	return browser, err
+}
+
+// startBrowser 는 새 Chromium 프로세스를 실행합니다. 돌려받은 launcher 로 프로세스와 임시 프로필을 정리할 수 있습니다.
+func startBrowser() (*rod.Browser, *launcher.Launcher, error) {
+	bin, err := findBrowserBinary()
+	if err != nil {
+		log.Printf("browser launch skipped: %v", err)
+		return nil, nil, &browserError{"브라우저 실행 파일을 찾지 못했습니다. 배포 이미지에 chromium이 포함되어 있는지 확인해주세요.", err}
+	}
+
+	l := launcher.New().
+		Leakless(false).
+		NoSandbox(true).
+		HeadlessNew(true).
+		// GPU 경로 제거
+		Append("--disable-gpu").
+		// 소프트웨어 GL까지 차단 → CPU 낭비↓
+		Append("--disable-software-rasterizer").
+		// 첫 실행 체크 제거
+		Append("--no-first-run").
+		Append("--no-default-browser-check").
+		// 대기열 유지에 중요 (타이머/렌더러 절전 방지)
+		Append("--disable-background-timer-throttling").
+		Append("--disable-renderer-backgrounding").
+		Append("--disable-backgrounding-occluded-windows").
+		// 창 사이즈 설정
+		Set("window-size", "1280,800").
+		Bin(bin)
+
+	u, err := l.Launch()
+	if err != nil {
+		log.Printf("browser launch failed with %q: %v", bin, err)
+		return nil, nil, &browserError{"브라우저 실행에 실패했습니다. 서버 로그를 확인해주세요.", err}
+	}
+
+	browser := rod.New().ControlURL(u)
+	if err := browser.Connect(); err != nil {
+		l.Kill()
+		l.Cleanup()
+		log.Printf("browser connect failed: %v", err)
+		return nil, nil, &browserError{"브라우저 연결에 실패했습니다. 서버 로그를 확인해주세요.", err}
+	}
+
+	log.Printf("브라우저를 실행했습니다. (%s)", bin)
+	return browser, l, nil
+}
+
+// newIncognitoPage 는 공유 브라우저에 세션 전용 시크릿 컨텍스트를 만들고 stealth 페이지를 엽니다.
+// 반환된 브라우저의 Close 는 이 컨텍스트만 정리하고 공유 프로세스는 유지합니다.
+func newIncognitoPage() (*rod.Browser, *rod.Page, error) {
+	shared, err := acquireBrowser()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	incognito, err := shared.Incognito()
+	if err != nil {
+		log.Printf("incognito context creation failed: %v", err)
+		return nil, nil, &browserError{"브라우저 세션 생성에 실패했습니다. 서버 로그를 확인해주세요.", err}
+	}
+
+	page, err := stealth.Page(incognito)
+	if err != nil {
+		_ = incognito.Close()
+		log.Printf("stealth page creation failed: %v", err)
+		return nil, nil, &browserError{"브라우저 페이지 초기화에 실패했습니다. 서버 로그를 확인해주세요.", err}
+	}
+
+	return incognito, page, nil
+}
+
+// newStandalonePage 는 공유 브라우저와 별도로 짧게 쓸 Chromium 을 실행하고 stealth 페이지를 엽니다.
+// 선택자 점검처럼 사용자 세션과 프로세스를 나누어야 할 때 씁니다. closeFn 은 프로세스와 임시 프로필을 정리합니다.
+func newStandalonePage() (*rod.Browser, *rod.Page, func(), error) {
+	browser, l, err := startBrowser()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	closeFn := func() {
+		_ = browser.Close()
+		l.Kill()
+		l.Cleanup()
+	}
+
+	page, err := stealth.Page(browser)
+	if err != nil {
+		closeFn()
+		log.Printf("stealth page creation failed: %v", err)
+		return nil, nil, nil, &browserError{"브라우저 페이지 초기화에 실패했습니다. 서버 로그를 확인해주세요.", err}
+	}
+	return browser, page, closeFn, nil
+}
+
+func findBrowserBinary() (string, error) {
+	candidates := []string{
+		os.Getenv("ROD_BROWSER_BIN"),
+		os.Getenv("BROWSER_BIN"),
+		"/usr/bin/chromium",
+		"/usr/bin/chromium-browser",
+	}
+
+	if found, ok := launcher.LookPath(); ok {
+		candidates = append(candidates, found)
+	}
+
+	seen := map[string]struct{}{}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		if _, exists := seen[candidate]; exists {
+			continue
+		}
+		seen[candidate] = struct{}{}
+
+		path, err := exec.LookPath(candidate)
+		if err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("no chromium binary found in candidates: %v", candidates)
+}
@@ -0,0 +1,450 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+const (
+	// 사이트마다 보관할 점검 보고서 수
+	canaryKeep = 14
+	// 점검 한 번에 쓸 수 있는 최대 시간
+	canaryTimeout = 5 * time.Minute
+)
+
+// canaryCheck 는 점검 항목 하나의 결과입니다.
+//
+// Kind 는 page(화면 이동), selector(요소 존재), text(요소의 문구), option(select 항목) 중 하나이고,
+// Key 는 선택자 설정의 키, Target 은 실제로 찾은 선택자나 문구입니다.
+type canaryCheck struct {
+	Page   string `json:"page"`
+	Kind   string `json:"kind"`
+	Key    string `json:"key,omitempty"`
+	Target string `json:"target"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+func (c canaryCheck) id() string {
+	return strings.Join([]string{c.Page, c.Kind, c.Key, c.Target}, "|")
+}
+
+func (c canaryCheck) String() string {
+	label := c.Target
+	if c.Key != "" {
+		label = fmt.Sprintf("%s(%s)", c.Target, c.Key)
+	}
+	return fmt.Sprintf("[%s] %s %s", c.Page, c.Kind, label)
+}
+
+// canaryDiff 는 직전 점검과 비교해 새로 사라진 항목과 다시 나타난 항목입니다.
+type canaryDiff struct {
+	Missing   []string `json:"missing"`
+	Recovered []string `json:"recovered"`
+}
+
+// canaryReport 는 사이트 하나를 로그인 없이 점검한 결과입니다.
+type canaryReport struct {
+	Site            string        `json:"site"`
+	Passed          bool          `json:"passed"`
+	Reason          string        `json:"reason"`
+	SelectorVersion string        `json:"selectorVersion"`
+	StartedAt       time.Time     `json:"startedAt"`
+	FinishedAt      time.Time     `json:"finishedAt"`
+	Error           string        `json:"error,omitempty"`
+	Failed          []canaryCheck `json:"failed"`
+	Diff            canaryDiff    `json:"diff"`
+	Checks          []canaryCheck `json:"checks"`
+}
+
+var canaries = &canaryHistory{reports: map[string][]*canaryReport{}}
+
+// canaryHistory 는 사이트별 최근 점검 보고서입니다. 점검은 한 번에 하나만 실행합니다.
+type canaryHistory struct {
+	mu      sync.Mutex
+	running bool
+	reports map[string][]*canaryReport
+}
+
+func (h *canaryHistory) start() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.running {
+		return false
+	}
+	h.running = true
+	return true
+}
+
+func (h *canaryHistory) finish() {
+	h.mu.Lock()
+	h.running = false
+	h.mu.Unlock()
+}
+
+func (h *canaryHistory) latest(site string) *canaryReport {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	list := h.reports[site]
+	if len(list) == 0 {
+		return nil
+	}
+	return list[len(list)-1]
+}
+
+func (h *canaryHistory) add(report *canaryReport) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	list := append(h.reports[report.Site], report)
+	if len(list) > canaryKeep {
+		list = list[len(list)-canaryKeep:]
+	}
+	h.reports[report.Site] = list
+}
+
+// list 는 모든 사이트의 보고서를 최신 순으로 돌려줍니다.
+func (h *canaryHistory) list() (bool, []*canaryReport) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var out []*canaryReport
+	for _, list := range h.reports {
+		out = append(out, list...)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].StartedAt.After(out[j].StartedAt)
+	})
+	return h.running, out
+}
+
+// diffCanary 는 직전 보고서와 실패 항목을 비교합니다. 직전 보고서가 없으면 지금 실패한 항목이 모두 새로 사라진 항목입니다.
+func diffCanary(prev *canaryReport, failed []canaryCheck) canaryDiff {
+	before := map[string]bool{}
+	if prev != nil {
+		for _, c := range prev.Failed {
+			before[c.id()] = true
+		}
+	}
+	now := map[string]bool{}
+
+	diff := canaryDiff{Missing: []string{}, Recovered: []string{}}
+	for _, c := range failed {
+		now[c.id()] = true
+		if !before[c.id()] {
+			diff.Missing = append(diff.Missing, c.String())
+		}
+	}
+	if prev != nil {
+		for _, c := range prev.Failed {
+			if !now[c.id()] {
+				diff.Recovered = append(diff.Recovered, c.String())
+			}
+		}
+	}
+	return diff
+}
+
+// canaryRun 은 점검 중인 페이지와 지금까지의 결과입니다. 사이트 어댑터의 Canary 가 이 값으로 화면을 돌며 항목을 확인합니다.
+type canaryRun struct {
+	session *userSession
+	page    *rod.Page
+	site    siteAdapter
+	current string
+	checks  []canaryCheck
+	// waitSeen 은 점검 중 한 번이라도 대기열 오버레이가 나타났는지입니다.
+	waitSeen bool
+}
+
+func (c *canaryRun) add(check canaryCheck) bool {
+	check.Page = c.current
+	c.checks = append(c.checks, check)
+	return check.OK
+}
+
+// visit 은 open 으로 화면을 옮기고 대기열을 처리합니다. 이동에 실패하면 false 이며 그 뒤 항목은 확인하지 않습니다.
+func (c *canaryRun) visit(name string, open func(page *rod.Page) error) bool {
+	c.current = name
+	check := canaryCheck{Kind: "page", Target: name, OK: true}
+	if err := open(c.page); err != nil {
+		check.OK = false
+		check.Detail = err.Error()
+	} else if readWaitPage(c.page, c.site.WaitPageSelector(), time.Second).Present {
+		c.waitSeen = true
+		c.session.handleWaitPage(c.page)
+	}
+	return c.add(check)
+}
+
+// expectWaitPage 는 대기열 선택자를 확인합니다. 대기열은 평소에는 없으므로
+// 점검 중 한 번도 나타나지 않았으면 선택자가 문법에 맞는지만 확인하고 그 사실을 Detail 에 남깁니다.
+func (c *canaryRun) expectWaitPage(key string) bool {
+	sel := siteSelector(c.site.ID(), key)
+	check := canaryCheck{Kind: "selector", Key: key, Target: sel, OK: true}
+	if c.waitSeen {
+		check.Detail = "대기열 화면에서 확인했습니다"
+		return c.add(check)
+	}
+
+	res, err := c.page.Eval(`(sel) => {
+		try {
+			document.querySelector(sel);
+			return true;
+		} catch (e) {
+			return false;
+		}
+	}`, sel)
+	switch {
+	case err != nil:
+		check.OK = false
+		check.Detail = err.Error()
+	case !res.Value.Bool():
+		check.OK = false
+		check.Detail = fmt.Sprintf("%s 는 올바른 선택자가 아닙니다", sel)
+	default:
+		check.Detail = "대기열이 없어 선택자 문법만 확인했습니다"
+	}
+	return c.add(check)
+}
+
+// expectSelector 는 선택자 설정의 key 에 해당하는 요소가 있는지 확인합니다.
+func (c *canaryRun) expectSelector(key string) bool {
+	sel := siteSelector(c.site.ID(), key)
+	check := canaryCheck{Kind: "selector", Key: key, Target: sel, OK: true}
+	if _, err := findElement(c.page, sel); err != nil {
+		check.OK = false
+		check.Detail = err.Error()
+	}
+	return c.add(check)
+}
+
+// expectText 는 key 선택자에 맞는 요소 중 글자가 textKey 문구와 같은 요소가 있는지 확인합니다.
+func (c *canaryRun) expectText(key, textKey string) bool {
+	sel := siteSelector(c.site.ID(), key)
+	text := siteText(c.site.ID(), textKey)
+	check := canaryCheck{Kind: "text", Key: key + "/" + textKey, Target: text, OK: true}
+
+	res, err := c.page.Eval(`(sel, text) => Array.from(document.querySelectorAll(sel))
+		.some((el) => (el.textContent || "").trim() === text)`, sel, text)
+	switch {
+	case err != nil:
+		check.OK = false
+		check.Detail = err.Error()
+	case !res.Value.Bool():
+		check.OK = false
+		check.Detail = fmt.Sprintf("%s 중 %q 문구가 없습니다", sel, text)
+	}
+	return c.add(check)
+}
+
+// expectOption 은 key 선택자의 <select> 에 want 항목(값 또는 표시 문구)이 있는지 확인합니다.
+func (c *canaryRun) expectOption(key, want string) bool {
+	sel := siteSelector(c.site.ID(), key)
+	check := canaryCheck{Kind: "option", Key: key, Target: want, OK: true}
+
+	res, err := c.page.Eval(`(sel) => {
+		const s = document.querySelector(sel);
+		if (!s || !s.options) return null;
+		return Array.from(s.options).map((o) => [o.value, (o.textContent || "").trim()]);
+	}`, sel)
+	if err != nil {
+		check.OK = false
+		check.Detail = err.Error()
+		return c.add(check)
+	}
+
+	var options [][2]string
+	if res.Value.Nil() || res.Value.Unmarshal(&options) != nil {
+		check.OK = false
+		check.Detail = fmt.Sprintf("%s 선택 상자를 읽지 못했습니다", sel)
+		return c.add(check)
+	}
+
+	var texts []string
+	for _, o := range options {
+		if o[0] == want || o[1] == strings.TrimSpace(want) {
+			return c.add(check)
+		}
+		if o[1] != "" {
+			texts = append(texts, o[1])
+		}
+	}
+	check.OK = false
+	check.Detail = "현재 항목: " + strings.Join(texts, ", ")
+	return c.add(check)
+}
+
+// targets 는 이 사이트에서 쓰는 프로필의 강습 구분과 구분별 강습 과정입니다.
+func (c *canaryRun) targets() ([]string, map[string][]string) {
+	var areas []string
+	entrances := map[string][]string{}
+	for _, p := range listProfiles() {
+		if p.Site != "" && p.Site != c.site.ID() {
+			continue
+		}
+		if _, seen := entrances[p.Area]; !seen {
+			areas = append(areas, p.Area)
+			entrances[p.Area] = nil
+		}
+		if !slices.Contains(entrances[p.Area], p.EntranceType) {
+			entrances[p.Area] = append(entrances[p.Area], p.EntranceType)
+		}
+	}
+	return areas, entrances
+}
+
+// runCanary 는 일회용 브라우저로 사이트 하나를 점검합니다. 로그인은 하지 않습니다.
+func runCanary(ctx context.Context, site siteAdapter, reason string) *canaryReport {
+	_, selStatus := currentSelectors()
+	report := &canaryReport{
+		Site:            site.ID(),
+		Reason:          reason,
+		SelectorVersion: selStatus.Version,
+		StartedAt:       time.Now(),
+		Failed:          []canaryCheck{},
+		Checks:          []canaryCheck{},
+	}
+
+	finish := func() *canaryReport {
+		report.FinishedAt = time.Now()
+		for _, c := range report.Checks {
+			if !c.OK {
+				report.Failed = append(report.Failed, c)
+			}
+		}
+		report.Passed = report.Error == "" && len(report.Failed) == 0
+		report.Diff = diffCanary(canaries.latest(site.ID()), report.Failed)
+		canaries.add(report)
+
+		if report.Passed {
+			log.Printf("[점검] %s 이상 없음 (%d개 항목)", site.ID(), len(report.Checks))
+		} else {
+			log.Printf("[점검] %s 실패: %s %v", site.ID(), report.Error, report.Diff.Missing)
+		}
+		return report
+	}
+
+	// 점검 중 브라우저가 멈추거나 죽어도 사용자 세션에 영향이 없도록 공유 브라우저와 별도 프로세스로 실행합니다.
+	browser, page, closeBrowser, err := newStandalonePage()
+	if err != nil {
+		report.Error = err.Error()
+		return finish()
+	}
+
+	// 일회용 세션이라 등록하지 않고, 대기열과 대화상자 처리만 빌려 씁니다.
+	session := newUserSession(browser, page, site)
+	session.startDialogs(page)
+	defer func() {
+		session.stopDialogs()
+		closeBrowser()
+		session.closeStatusChannel()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, canaryTimeout)
+	defer cancel()
+	page, done, err := session.begin(ctx)
+	if err != nil {
+		report.Error = err.Error()
+		return finish()
+	}
+	defer done()
+
+	run := &canaryRun{session: session, page: page, site: site}
+	site.Canary(run)
+	report.Checks = run.checks
+	return finish()
+}
+
+// runCanaries 는 등록된 모든 사이트를 차례로 점검합니다. 이미 점검 중이면 false 입니다.
+func runCanaries(ctx context.Context, reason string) ([]*canaryReport, bool) {
+	if !canaries.start() {
+		return nil, false
+	}
+	defer canaries.finish()
+
+	var out []*canaryReport
+	for _, site := range sites {
+		out = append(out, runCanary(ctx, site, reason))
+	}
+	return out, true
+}
+
+// scheduleCanary 는 매일 CANARY_TIME(KST, 기본 06:00)에 점검을 실행합니다. off 면 실행하지 않습니다.
+func scheduleCanary() {
+	value := strings.TrimSpace(os.Getenv("CANARY_TIME"))
+	if value == "" {
+		value = "06:00"
+	}
+	if value == "off" {
+		log.Printf("CANARY_TIME=off 이므로 정기 선택자 점검을 하지 않습니다.")
+		return
+	}
+
+	at, err := time.ParseInLocation("15:04", value, kst)
+	if err != nil {
+		log.Printf("CANARY_TIME 값 %q을(를) 해석하지 못해 기본값 06:00을 사용합니다.", value)
+		at = time.Date(0, 1, 1, 6, 0, 0, 0, kst)
+	}
+
+	for {
+		now := time.Now().In(kst)
+		next := time.Date(now.Year(), now.Month(), now.Day(), at.Hour(), at.Minute(), 0, 0, kst)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		time.Sleep(time.Until(next))
+
+		if _, ok := runCanaries(context.Background(), "정기 점검"); !ok {
+			log.Printf("[점검] 이전 점검이 아직 진행 중이라 정기 점검을 건너뜁니다.")
+		}
+	}
+}
+
+// Canary 는 최근 점검 보고서를 돌려줍니다(GET). POST 는 바로 점검을 실행하고 그 결과를 돌려줍니다.
+func Canary(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		running, reports := canaries.list()
+		resp := struct {
+			Running bool            `json:"running"`
+			Reports []*canaryReport `json:"reports"`
+		}{
+			Running: running,
+			Reports: reports,
+		}
+		if resp.Reports == nil {
+			resp.Reports = []*canaryReport{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("점검 보고서 응답 인코딩 실패: %v", err)
+		}
+	case http.MethodPost:
+		if !requireAdmin(w, r) {
+			return
+		}
+		reports, ok := runCanaries(r.Context(), "수동 요청")
+		if !ok {
+			http.Error(w, "이미 점검이 진행 중입니다.", http.StatusConflict)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(reports); err != nil {
+			log.Printf("점검 보고서 응답 인코딩 실패: %v", err)
+		}
+	default:
+		http.Error(w, "GET, POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffCanary(t *testing.T) {
+	area := canaryCheck{Page: "강습 목록", Kind: "selector", Key: "area", Target: "#areaGbn"}
+	button := canaryCheck{Page: "강습 목록 (호계스쿼시)", Kind: "selector", Key: "lessonButton", Target: "a.common_btn.regist"}
+	option := canaryCheck{Page: "강습 목록 (호계스쿼시)", Kind: "option", Key: "entrance", Target: "화목(강습)"}
+	// 같은 항목이라도 선택자가 바뀌면 다른 항목으로 봅니다.
+	areaRenamed := canaryCheck{Page: "강습 목록", Kind: "selector", Key: "area", Target: "#areaGbn2"}
+
+	tests := []struct {
+		name      string
+		prev      *canaryReport
+		failed    []canaryCheck
+		missing   []string
+		recovered []string
+	}{
+		{"첫 점검, 이상 없음", nil, nil, []string{}, []string{}},
+		{"첫 점검, 실패 항목", nil, []canaryCheck{area, button}, []string{area.String(), button.String()}, []string{}},
+		{"계속 실패", &canaryReport{Failed: []canaryCheck{area}}, []canaryCheck{area}, []string{}, []string{}},
+		{"새로 실패", &canaryReport{Failed: []canaryCheck{area}}, []canaryCheck{area, option}, []string{option.String()}, []string{}},
+		{"복구", &canaryReport{Failed: []canaryCheck{area, button}}, []canaryCheck{button}, []string{}, []string{area.String()}},
+		{"선택자 변경", &canaryReport{Failed: []canaryCheck{area}}, []canaryCheck{areaRenamed}, []string{areaRenamed.String()}, []string{area.String()}},
+		{"모두 복구", &canaryReport{Failed: []canaryCheck{area, option}}, nil, []string{}, []string{area.String(), option.String()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := diffCanary(tt.prev, tt.failed)
+			if !reflect.DeepEqual(diff.Missing, tt.missing) {
+				t.Errorf("Missing = %q, want %q", diff.Missing, tt.missing)
+			}
+			if !reflect.DeepEqual(diff.Recovered, tt.recovered) {
+				t.Errorf("Recovered = %q, want %q", diff.Recovered, tt.recovered)
+			}
+		})
+	}
+}
+
+func TestCanaryCheckString(t *testing.T) {
+	tests := []struct {
+		check canaryCheck
+		want  string
+	}{
+		{canaryCheck{Page: "강습 목록", Kind: "selector", Key: "lessonButton", Target: "a.regist"}, "[강습 목록] selector a.regist(lessonButton)"},
+		{canaryCheck{Page: "메인 페이지", Kind: "page", Target: "메인 페이지"}, "[메인 페이지] page 메인 페이지"},
+	}
+	for _, tt := range tests {
+		if got := tt.check.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/go-rod/rod"
+)
+
+// 구버전 클라이언트(squash-helper client)는 사용자의 디버깅 크롬 탭에 붙어 버튼 하나에 한 단계씩 실행합니다.
+// 주소, 선택자, 대상 강습을 따로 두지 않도록 서버의 기본 사이트 어댑터, 선택자 설정, 프로필을 그대로 씁니다.
+
+// LoadClientConfig 는 클라이언트가 쓰는 선택자 설정과 프로필을 불러옵니다.
+func LoadClientConfig() error {
+	if err := loadSelectors(); err != nil {
+		return err
+	}
+	return loadProfiles()
+}
+
+// LessonListURL 은 기본 사이트의 강습 목록(신청) 페이지입니다.
+func LessonListURL() string {
+	return defaultSite.LessonListURL()
+}
+
+// ClientStep 은 클라이언트의 단계 번호(1: 강습 구분, 2: 강습 과정, 3: 강습 시간)를 프로필 값으로 실행합니다.
+// 선택할 항목이나 누를 버튼이 없으면 ok 는 false 이고, label 은 화면에 보여줄 단계 이름입니다.
+func ClientStep(page *rod.Page, profileName, code string) (label string, ok bool, err error) {
+	profile, found := findSiteProfile(profileName, defaultSite)
+	if !found {
+		return "", false, fmt.Errorf("알 수 없는 대상 프로필입니다: %s", profileName)
+	}
+
+	switch code {
+	case "1":
+		ok, err = defaultSite.SelectArea(page, profile)
+		return "강습 구분 선택", ok, err
+	case "2":
+		ok, err = defaultSite.SelectEntrance(page, profile)
+		return "강습 과정 선택", ok, err
+	case "3":
+		ok, err = defaultSite.ClickLesson(page, profile)
+		return "강습 시간 선택", ok, err
+	}
+	return "", false, fmt.Errorf("알 수 없는 단계입니다: %s", code)
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 기본 대화상자 규칙. DIALOGS_FILE 환경 변수로 외부 파일을 지정하면 그 파일을 우선 사용합니다.
+//
+//go:embed config/dialogs.json
+var defaultDialogsJSON []byte
+
+const (
+	dialogAccept  = "accept"
+	dialogDismiss = "dismiss"
+
+	// 로그인 페이지 진입부터 로그인 완료까지의 단계
+	dialogStageLogin = "login"
+
+	// 세션마다 보관할 최근 대화상자 수
+	dialogKeep = 50
+)
+
+// dialogRule 은 대화상자 하나를 어떻게 처리할지 정합니다. 위에서부터 처음 맞는 규칙을 씁니다.
+//
+// Stage 가 있으면 세션이 그 단계일 때만, Type 이 있으면 그 종류(alert, confirm, prompt, beforeunload)일 때만,
+// Match 가 있으면 대화상자 문구가 정규식에 맞을 때만 적용됩니다.
+// Times 가 있으면 단계가 바뀐 뒤 그 횟수만큼만 적용됩니다.
+type dialogRule struct {
+	Name       string `json:"name"`
+	Stage      string `json:"stage,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Match      string `json:"match,omitempty"`
+	Action     string `json:"action"`
+	PromptText string `json:"promptText,omitempty"`
+	Times      int    `json:"times,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+type dialogConfig struct {
+	Default string       `json:"default"`
+	Rules   []dialogRule `json:"rules"`
+}
+
+// dialogRecord 는 처리한 대화상자 하나의 기록입니다.
+type dialogRecord struct {
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Rule    string    `json:"rule"`
+	Action  string    `json:"action"`
+	At      time.Time `json:"at"`
+}
+
+var (
+	dialogMu    sync.RWMutex
+	dialogRules dialogConfig
+)
+
+func loadDialogRules() error {
+	data := defaultDialogsJSON
+	source := "기본 설정"
+
+	if path := strings.TrimSpace(os.Getenv("DIALOGS_FILE")); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("대화상자 규칙 파일 읽기 실패 (%s): %w", path, err)
+		}
+		data = b
+		source = path
+	}
+
+	cfg, err := parseDialogRules(data)
+	if err != nil {
+		return fmt.Errorf("대화상자 규칙 파싱 실패 (%s): %w", source, err)
+	}
+
+	dialogMu.Lock()
+	dialogRules = cfg
+	dialogMu.Unlock()
+
+	log.Printf("대화상자 규칙 %d개를 불러왔습니다. (%s)", len(cfg.Rules), source)
+	return nil
+}
+
+func parseDialogRules(data []byte) (dialogConfig, error) {
+	var cfg dialogConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return dialogConfig{}, err
+	}
+
+	if cfg.Default == "" {
+		cfg.Default = dialogAccept
+	}
+	if cfg.Default != dialogAccept && cfg.Default != dialogDismiss {
+		return dialogConfig{}, fmt.Errorf("default는 accept 또는 dismiss 이어야 합니다")
+	}
+
+	seen := map[string]struct{}{}
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		rule.Name = strings.TrimSpace(rule.Name)
+		if rule.Name == "" {
+			return dialogConfig{}, fmt.Errorf("%d번째 규칙에 name이 없습니다", i+1)
+		}
+		if _, exists := seen[rule.Name]; exists {
+			return dialogConfig{}, fmt.Errorf("규칙 이름 %q이(가) 중복되었습니다", rule.Name)
+		}
+		seen[rule.Name] = struct{}{}
+
+		if rule.Action != dialogAccept && rule.Action != dialogDismiss {
+			return dialogConfig{}, fmt.Errorf("규칙 %q의 action은 accept 또는 dismiss 이어야 합니다", rule.Name)
+		}
+		if rule.Match != "" {
+			pattern, err := regexp.Compile(rule.Match)
+			if err != nil {
+				return dialogConfig{}, fmt.Errorf("규칙 %q의 match 정규식 오류: %w", rule.Name, err)
+			}
+			rule.pattern = pattern
+		}
+	}
+
+	return cfg, nil
+}
+
+// dialogHandler 는 세션이 살아 있는 동안 페이지의 JavaScript 대화상자를 규칙에 따라 처리합니다.
+type dialogHandler struct {
+	mu     sync.Mutex
+	stage  string
+	hits   map[string]int
+	recent []dialogRecord
+	stop   context.CancelFunc
+}
+
+// decide 는 대화상자에 적용할 규칙을 고르고 적용 횟수를 올립니다.
+func (d *dialogHandler) decide(dialogType, message string) (dialogRule, bool) {
+	dialogMu.RLock()
+	cfg := dialogRules
+	dialogMu.RUnlock()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, rule := range cfg.Rules {
+		if rule.Stage != "" && rule.Stage != d.stage {
+			continue
+		}
+		if rule.Type != "" && rule.Type != dialogType {
+			continue
+		}
+		if rule.pattern != nil && !rule.pattern.MatchString(message) {
+			continue
+		}
+		if rule.Times > 0 && d.hits[rule.Name] >= rule.Times {
+			continue
+		}
+		d.hits[rule.Name]++
+		return rule, true
+	}
+
+	return dialogRule{Name: "default", Action: cfg.Default}, false
+}
+
+func (d *dialogHandler) record(rec dialogRecord) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.recent = append(d.recent, rec)
+	if len(d.recent) > dialogKeep {
+		d.recent = d.recent[len(d.recent)-dialogKeep:]
+	}
+}
+
+func (d *dialogHandler) history() []dialogRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]dialogRecord{}, d.recent...)
+}
+
+// startDialogs 는 세션 페이지의 대화상자 처리를 시작합니다. 세션이 끝날 때 stopDialogs 로 멈춥니다.
+func (s *userSession) startDialogs(page *rod.Page) {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &dialogHandler{
+		hits: make(map[string]int),
+		stop: cancel,
+	}
+
+	s.dialogMu.Lock()
+	prev := s.dialogs
+	s.dialogs = d
+	s.dialogMu.Unlock()
+	if prev != nil {
+		prev.stop()
+	}
+
+	p := page.Context(ctx)
+	wait := p.EachEvent(func(e *proto.PageJavascriptDialogOpening) {
+		dialogType := string(e.Type)
+		rule, _ := d.decide(dialogType, e.Message)
+
+		accept := rule.Action == dialogAccept
+		err := proto.PageHandleJavaScriptDialog{
+			Accept:     accept,
+			PromptText: rule.PromptText,
+		}.Call(p)
+		if err != nil {
+			log.Printf("대화상자 처리 실패: %v", err)
+		}
+
+		d.record(dialogRecord{
+			Type:    dialogType,
+			Message: e.Message,
+			Rule:    rule.Name,
+			Action:  rule.Action,
+			At:      time.Now(),
+		})
+
+		verb := "확인"
+		if !accept {
+			verb = "취소"
+		}
+		s.pushStatus("dialog", fmt.Sprintf("[대화상자] %s → %s (%s)", strings.TrimSpace(e.Message), verb, rule.Name))
+	})
+	go wait()
+}
+
+func (s *userSession) stopDialogs() {
+	if s == nil {
+		return
+	}
+
+	s.dialogMu.Lock()
+	d := s.dialogs
+	s.dialogs = nil
+	s.dialogMu.Unlock()
+
+	if d != nil {
+		d.stop()
+	}
+}
+
+// setDialogStage 는 대화상자 규칙의 적용 단계를 바꾸고 횟수 제한을 초기화합니다.
+func (s *userSession) setDialogStage(stage string) {
+	s.dialogMu.Lock()
+	d := s.dialogs
+	s.dialogMu.Unlock()
+
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	d.stage = stage
+	d.hits = make(map[string]int)
+	d.mu.Unlock()
+}
+
+// Dialogs 는 대화상자 규칙과 세션에서 처리한 최근 대화상자를 돌려줍니다.
+func Dialogs(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	dialogMu.RLock()
+	cfg := dialogRules
+	dialogMu.RUnlock()
+
+	resp := struct {
+		Stage   string         `json:"stage"`
+		Default string         `json:"default"`
+		Rules   []dialogRule   `json:"rules"`
+		Recent  []dialogRecord `json:"recent"`
+	}{
+		Default: cfg.Default,
+		Rules:   cfg.Rules,
+		Recent:  []dialogRecord{},
+	}
+
+	session.dialogMu.Lock()
+	d := session.dialogs
+	session.dialogMu.Unlock()
+	if d != nil {
+		d.mu.Lock()
+		resp.Stage = d.stage
+		d.mu.Unlock()
+		resp.Recent = d.history()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("대화상자 응답 인코딩 실패: %v", err)
+	}
+}
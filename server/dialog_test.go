@@ -0,0 +1,110 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseDialogRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"기본 설정", string(defaultDialogsJSON), ""},
+		{"default 생략", `{"rules":[]}`, ""},
+		{"잘못된 default", `{"default":"ignore","rules":[]}`, "default"},
+		{"이름 없음", `{"rules":[{"action":"accept"}]}`, "name"},
+		{"이름 중복", `{"rules":[{"name":"a","action":"accept"},{"name":" a ","action":"dismiss"}]}`, "중복"},
+		{"잘못된 action", `{"rules":[{"name":"a","action":"close"}]}`, "action"},
+		{"잘못된 정규식", `{"rules":[{"name":"a","action":"accept","match":"("}]}`, "정규식"},
+		{"JSON 오류", `{"rules":`, "unexpected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseDialogRules([]byte(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseDialogRules: %v", err)
+				}
+				if cfg.Default != dialogAccept && cfg.Default != dialogDismiss {
+					t.Errorf("Default = %q", cfg.Default)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// useDialogRules 는 테스트 동안 전역 대화상자 규칙을 바꿉니다.
+func useDialogRules(t *testing.T, data string) {
+	t.Helper()
+	cfg, err := parseDialogRules([]byte(data))
+	if err != nil {
+		t.Fatalf("parseDialogRules: %v", err)
+	}
+
+	dialogMu.Lock()
+	prev := dialogRules
+	dialogRules = cfg
+	dialogMu.Unlock()
+
+	t.Cleanup(func() {
+		dialogMu.Lock()
+		dialogRules = prev
+		dialogMu.Unlock()
+	})
+}
+
+func TestDialogDecide(t *testing.T) {
+	useDialogRules(t, string(defaultDialogsJSON))
+
+	type call struct {
+		stage, dialogType, message string
+		rule, action               string
+		matched                    bool
+	}
+	steps := []call{
+		// 로그인 단계의 첫 confirm 은 취소, 두 번째는 확인, 그 뒤는 기본값입니다.
+		{dialogStageLogin, "confirm", "개인정보 수집에 동의하십니까?", "login-first-confirm", dialogDismiss, true},
+		{dialogStageLogin, "confirm", "통합 로그인으로 이동하시겠습니까?", "login-second-confirm", dialogAccept, true},
+		{dialogStageLogin, "confirm", "다시 묻습니다", "default", dialogAccept, false},
+		{dialogStageLogin, "alert", "알림", "default", dialogAccept, false},
+		// 단계가 바뀌면 적용 횟수가 초기화되지만 login 단계 규칙은 적용되지 않습니다.
+		{"", "confirm", "개인정보 수집에 동의하십니까?", "default", dialogAccept, false},
+		{"", "alert", "이미 신청한 강습입니다.", "already-applied", dialogAccept, true},
+		{"", "confirm", "강습을 신청하시겠습니까?", "apply-confirm", dialogAccept, true},
+		{"", "alert", "강습을 신청하시겠습니까?", "default", dialogAccept, false},
+		// 단계를 다시 login 으로 바꾸면 times 가 초기화됩니다.
+		{dialogStageLogin, "confirm", "동의", "login-first-confirm", dialogDismiss, true},
+	}
+
+	d := &dialogHandler{hits: map[string]int{}}
+	stage := ""
+	for i, st := range steps {
+		if st.stage != stage {
+			d.stage, d.hits, stage = st.stage, map[string]int{}, st.stage
+		}
+		rule, matched := d.decide(st.dialogType, st.message)
+		if rule.Name != st.rule || rule.Action != st.action || matched != st.matched {
+			t.Errorf("%d: decide(%s, %q) at stage %q = %s/%s/%v, want %s/%s/%v",
+				i+1, st.dialogType, st.message, st.stage, rule.Name, rule.Action, matched, st.rule, st.action, st.matched)
+		}
+	}
+}
+
+func TestDialogDecideDefaultDismiss(t *testing.T) {
+	useDialogRules(t, `{"default":"dismiss","rules":[{"name":"leave","type":"beforeunload","action":"accept"}]}`)
+
+	d := &dialogHandler{hits: map[string]int{}}
+	if rule, matched := d.decide("beforeunload", ""); !matched || rule.Action != dialogAccept {
+		t.Errorf("beforeunload = %s/%v, want leave accept", rule.Action, matched)
+	}
+	if rule, matched := d.decide("confirm", "삭제할까요?"); matched || rule.Action != dialogDismiss {
+		t.Errorf("confirm = %s/%v, want default dismiss", rule.Action, matched)
+	}
+}
@@ -0,0 +1,512 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// 세션 하나가 보관할 최대 요청 수
+const harMaxEntries = 5000
+
+const harMask = "***"
+
+var (
+	// 이름 전체가 이 패턴에 맞는 폼 필드, JSON 키, 쿼리 파라미터 값은 가립니다.
+	// 비밀번호/토큰 외에 로그인 아이디(login_id, userId 등)도 개인정보이므로 가립니다.
+	// author, bypass 같은 이름이나 강습을 가리키는 id, lessonId 는 디버깅에 필요하므로 그대로 둡니다.
+	harSensitiveKey = regexp.MustCompile(`(?i)^(?:(?:login|user|member|usr|new|old|access|refresh|api|client|session)[_-]?)?(?:pw|pwd|pass|passwd|password|secret|token|auth|authorization)$|^(?:login|user|member|usr)[_-]?id$`)
+	// 값을 가리는 헤더
+	harSensitiveHeaders = map[string]bool{
+		"cookie":        true,
+		"set-cookie":    true,
+		"authorization": true,
+	}
+)
+
+// HAR 1.2 형식 (http://www.softwareishard.com/blog/har-12-spec/)
+type harLog struct {
+	Log harContent `json:"log"`
+}
+
+type harContent struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harNameVal `json:"cookies"`
+	Headers     []harNameVal `json:"headers"`
+	QueryString []harNameVal `json:"queryString"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Cookies     []harNameVal `json:"cookies"`
+	Headers     []harNameVal `json:"headers"`
+	Content     harBody      `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harNameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harBody struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	SSL     float64 `json:"ssl"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harPending 은 응답이 끝나기 전의 요청입니다.
+type harPending struct {
+	entry   harEntry
+	started proto.MonotonicTime
+	timing  *proto.NetworkResourceTiming
+}
+
+// harRecorder 는 세션 페이지의 CDP 네트워크 이벤트를 HAR 항목으로 모읍니다.
+type harRecorder struct {
+	mu        sync.Mutex
+	pending   map[proto.NetworkRequestID]*harPending
+	entries   []harEntry
+	dropped   int
+	startedAt time.Time
+	stop      context.CancelFunc
+}
+
+func maskValue(name, value string) string {
+	if harSensitiveKey.MatchString(name) {
+		return harMask
+	}
+	return value
+}
+
+func harHeaders(headers proto.NetworkHeaders) []harNameVal {
+	out := make([]harNameVal, 0, len(headers))
+	for name, value := range headers {
+		v := value.String()
+		if harSensitiveHeaders[strings.ToLower(name)] {
+			v = harMask
+		}
+		out = append(out, harNameVal{Name: name, Value: v})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func harHeader(headers proto.NetworkHeaders, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v.String()
+		}
+	}
+	return ""
+}
+
+// maskURL 은 쿼리 문자열의 민감한 값을 가린 URL 과 쿼리 목록을 돌려줍니다.
+func maskURL(raw string) (string, []harNameVal) {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw, []harNameVal{}
+	}
+
+	query := u.Query()
+	out := []harNameVal{}
+	for name, values := range query {
+		for i, v := range values {
+			values[i] = maskValue(name, v)
+			out = append(out, harNameVal{Name: name, Value: values[i]})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	u.RawQuery = query.Encode()
+	return u.String(), out
+}
+
+// maskPostData 는 폼/JSON 본문의 비밀번호 같은 값을 가립니다.
+// 형식을 알 수 없는 본문에 민감한 이름이 보이면 본문 전체를 가립니다.
+func maskPostData(mimeType, body string) string {
+	switch {
+	case strings.Contains(mimeType, "application/x-www-form-urlencoded"):
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			break
+		}
+		for name, values := range form {
+			for i, v := range values {
+				values[i] = maskValue(name, v)
+			}
+		}
+		return form.Encode()
+
+	case strings.Contains(mimeType, "json"):
+		var v any
+		if err := json.Unmarshal([]byte(body), &v); err != nil {
+			break
+		}
+		data, err := json.Marshal(maskJSON(v))
+		if err != nil {
+			break
+		}
+		return string(data)
+	}
+
+	if containsSensitiveKey(body) {
+		return harMask
+	}
+	return body
+}
+
+// containsSensitiveKey 는 형식을 모르는 본문에 민감한 이름이 낱말로 들어 있는지 확인합니다.
+func containsSensitiveKey(body string) bool {
+	words := strings.FieldsFunc(body, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-'
+	})
+	for _, w := range words {
+		if harSensitiveKey.MatchString(w) {
+			return true
+		}
+	}
+	return false
+}
+
+func maskJSON(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if harSensitiveKey.MatchString(k) {
+				t[k] = harMask
+				continue
+			}
+			t[k] = maskJSON(child)
+		}
+	case []any:
+		for i, child := range t {
+			t[i] = maskJSON(child)
+		}
+	}
+	return v
+}
+
+func harRequestFrom(req *proto.NetworkRequest) harRequest {
+	u, query := maskURL(req.URL)
+	out := harRequest{
+		Method:      req.Method,
+		URL:         u,
+		HTTPVersion: "",
+		Cookies:     []harNameVal{},
+		Headers:     harHeaders(req.Headers),
+		QueryString: query,
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+
+	body := req.PostData
+	if body == "" {
+		for _, entry := range req.PostDataEntries {
+			body += string(entry.Bytes)
+		}
+	}
+	if body != "" {
+		mimeType := harHeader(req.Headers, "Content-Type")
+		out.PostData = &harPostData{MimeType: mimeType, Text: maskPostData(mimeType, body)}
+		out.BodySize = len(body)
+	}
+	return out
+}
+
+func harResponseFrom(res *proto.NetworkResponse) harResponse {
+	return harResponse{
+		Status:      res.Status,
+		StatusText:  res.StatusText,
+		HTTPVersion: res.Protocol,
+		Cookies:     []harNameVal{},
+		Headers:     harHeaders(res.Headers),
+		Content:     harBody{Size: -1, MimeType: res.MIMEType},
+		RedirectURL: harHeader(res.Headers, "Location"),
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+}
+
+// span 은 CDP 타이밍의 두 지점(ms) 사이 간격이며, 값이 없으면 -1 입니다.
+func span(start, end float64) float64 {
+	if start < 0 || end < 0 {
+		return -1
+	}
+	return end - start
+}
+
+func harTimingsFrom(t *proto.NetworkResourceTiming, total float64) harTimings {
+	if t == nil {
+		return harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1, Wait: total}
+	}
+
+	timings := harTimings{
+		Blocked: -1,
+		DNS:     span(t.DNSStart, t.DNSEnd),
+		Connect: span(t.ConnectStart, t.ConnectEnd),
+		SSL:     span(t.SslStart, t.SslEnd),
+		Send:    max(span(t.SendStart, t.SendEnd), 0),
+		Wait:    max(span(t.SendEnd, t.ReceiveHeadersEnd), 0),
+	}
+	timings.Receive = max(total-t.ReceiveHeadersEnd, 0)
+	return timings
+}
+
+func (h *harRecorder) begin(e *proto.NetworkRequestWillBeSent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 리다이렉트는 같은 요청 ID 로 다시 오므로 앞의 요청을 리다이렉트 응답으로 마무리합니다.
+	if prev, ok := h.pending[e.RequestID]; ok && e.RedirectResponse != nil {
+		prev.entry.Response = harResponseFrom(e.RedirectResponse)
+		prev.timing = e.RedirectResponse.Timing
+		h.finishLocked(e.RequestID, e.Timestamp)
+	}
+
+	h.pending[e.RequestID] = &harPending{
+		entry: harEntry{
+			StartedDateTime: e.WallTime.Time(),
+			Request:         harRequestFrom(e.Request),
+			Response:        harResponse{Cookies: []harNameVal{}, Headers: []harNameVal{}, HeadersSize: -1, BodySize: -1},
+			ResourceType:    string(e.Type),
+		},
+		started: e.Timestamp,
+	}
+}
+
+func (h *harRecorder) respond(e *proto.NetworkResponseReceived) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.pending[e.RequestID]
+	if !ok {
+		return
+	}
+	p.entry.Response = harResponseFrom(e.Response)
+	p.entry.Request.HTTPVersion = e.Response.Protocol
+	p.entry.ServerIPAddress = e.Response.RemoteIPAddress
+	p.timing = e.Response.Timing
+}
+
+func (h *harRecorder) finish(id proto.NetworkRequestID, at proto.MonotonicTime, size float64, errText string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	p, ok := h.pending[id]
+	if !ok {
+		return
+	}
+	if size > 0 {
+		p.entry.Response.BodySize = int(size)
+	}
+	p.entry.Error = errText
+	h.finishLocked(id, at)
+}
+
+func (h *harRecorder) finishLocked(id proto.NetworkRequestID, at proto.MonotonicTime) {
+	p := h.pending[id]
+	delete(h.pending, id)
+
+	total := float64(at-p.started) * 1000
+	p.entry.Time = max(total, 0)
+	p.entry.Timings = harTimingsFrom(p.timing, p.entry.Time)
+
+	if len(h.entries) >= harMaxEntries {
+		h.dropped++
+		return
+	}
+	h.entries = append(h.entries, p.entry)
+}
+
+func (h *harRecorder) export() harLog {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := append([]harEntry(nil), h.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return harLog{Log: harContent{
+		Version: "1.2",
+		Creator: harCreator{Name: "squash-helper", Version: "1"},
+		Entries: entries,
+	}}
+}
+
+func (h *harRecorder) running() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stop != nil
+}
+
+// startRecording 은 세션 페이지의 네트워크 기록을 새로 시작합니다.
+func startRecording(page *rod.Page) *harRecorder {
+	ctx, cancel := context.WithCancel(context.Background())
+	h := &harRecorder{
+		pending:   make(map[proto.NetworkRequestID]*harPending),
+		startedAt: time.Now(),
+		stop:      cancel,
+	}
+
+	// EachEvent 가 Network 도메인을 켜고, 기록이 끝나면 원래대로 돌려놓습니다.
+	wait := page.Context(ctx).EachEvent(
+		func(e *proto.NetworkRequestWillBeSent) { h.begin(e) },
+		func(e *proto.NetworkResponseReceived) { h.respond(e) },
+		func(e *proto.NetworkLoadingFinished) { h.finish(e.RequestID, e.Timestamp, e.EncodedDataLength, "") },
+		func(e *proto.NetworkLoadingFailed) { h.finish(e.RequestID, e.Timestamp, 0, e.ErrorText) },
+	)
+	go wait()
+	return h
+}
+
+func (h *harRecorder) halt() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stop != nil {
+		h.stop()
+		h.stop = nil
+	}
+	if h.dropped > 0 {
+		log.Printf("네트워크 기록이 가득 차 요청 %d건을 버렸습니다.", h.dropped)
+	}
+}
+
+func (s *userSession) stopRecording() bool {
+	if s == nil {
+		return false
+	}
+
+	s.harMu.Lock()
+	h := s.har
+	s.harMu.Unlock()
+
+	if h == nil || !h.running() {
+		return false
+	}
+	h.halt()
+	return true
+}
+
+// Har 는 세션의 네트워크 기록을 관리합니다.
+//
+//	GET    /har  기록을 HAR 파일로 내려받기
+//	POST   /har  기록 시작 (이전 기록은 지워집니다)
+//	DELETE /har  기록 중지 (기록은 남아 있어 내려받을 수 있습니다)
+func Har(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		session.harMu.Lock()
+		h := session.har
+		session.harMu.Unlock()
+		if h == nil {
+			http.Error(w, "네트워크 기록이 없습니다. 먼저 기록을 시작해주세요.", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="session-%s.har"`, h.startedAt.In(kst).Format("20060102-150405")))
+		if err := json.NewEncoder(w).Encode(h.export()); err != nil {
+			log.Printf("HAR 응답 인코딩 실패: %v", err)
+		}
+
+	case http.MethodPost:
+		session.mu.Lock()
+		page := session.page
+		session.mu.Unlock()
+		if page == nil {
+			session.failStep(w, errNoPage)
+			return
+		}
+
+		session.harMu.Lock()
+		if session.har != nil && session.har.running() {
+			session.harMu.Unlock()
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("이미 네트워크를 기록하고 있습니다."))
+			return
+		}
+		session.har = startRecording(page)
+		session.harMu.Unlock()
+
+		session.pushInfo("네트워크 기록을 시작했습니다.")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("네트워크 기록 시작"))
+
+	case http.MethodDelete:
+		if !session.stopRecording() {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("기록 중인 네트워크가 없습니다."))
+			return
+		}
+		session.pushInfo("네트워크 기록을 중지했습니다.")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("네트워크 기록 중지"))
+
+	default:
+		http.Error(w, "GET, POST, DELETE 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"encoding/json"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+func TestMaskValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		masked bool
+	}{
+		{"login_pwd", true},
+		{"password", true},
+		{"userPassword", true},
+		{"PW", true},
+		{"access_token", true},
+		{"login_id", true},
+		{"loginId", true},
+		{"userId", true},
+		{"member-id", true},
+		{"new_password", true},
+		{"Authorization", true},
+		// 이름 일부에 민감한 낱말이 있어도 가리지 않습니다.
+		{"id", false},
+		{"lessonId", false},
+		{"groupId", false},
+		{"author", false},
+		{"passage", false},
+		{"bypass", false},
+		{"tokenizer", false},
+		{"groupSeq", false},
+		{"lessonSeq", false},
+		{"areaGbn", false},
+		{"width", false},
+		{"page", false},
+	}
+
+	for _, tt := range tests {
+		got := maskValue(tt.name, "value")
+		if masked := got == harMask; masked != tt.masked {
+			t.Errorf("maskValue(%q) = %q, masked %v; want masked %v", tt.name, got, masked, tt.masked)
+		}
+	}
+}
+
+func TestMaskURL(t *testing.T) {
+	raw := "https://newsso.anyang.go.kr/login?login_id=hong&login_pwd=secret1&returnUrl=%2Fmain&page=2"
+	masked, query := maskURL(raw)
+
+	if strings.Contains(masked, "hong") || strings.Contains(masked, "secret1") {
+		t.Errorf("maskURL leaked credentials: %s", masked)
+	}
+	u, err := url.Parse(masked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := u.Query().Get("returnUrl"); got != "/main" {
+		t.Errorf("returnUrl = %q, want /main", got)
+	}
+
+	want := []harNameVal{
+		{Name: "login_id", Value: harMask},
+		{Name: "login_pwd", Value: harMask},
+		{Name: "page", Value: "2"},
+		{Name: "returnUrl", Value: "/main"},
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+
+	if got, q := maskURL("https://www.auc.or.kr/hogye/main/view"); got != "https://www.auc.or.kr/hogye/main/view" || len(q) != 0 {
+		t.Errorf("maskURL without query = %q, %v", got, q)
+	}
+}
+
+func TestMaskPostData(t *testing.T) {
+	tests := []struct {
+		name     string
+		mimeType string
+		body     string
+		leaks    []string
+		keeps    []string
+	}{
+		{
+			name:     "폼",
+			mimeType: "application/x-www-form-urlencoded; charset=UTF-8",
+			body:     "login_id=hong&login_pwd=p%40ss&areaGbn=squash",
+			leaks:    []string{"hong", "p%40ss", "p@ss"},
+			keeps:    []string{"areaGbn=squash"},
+		},
+		{
+			name:     "JSON 중첩",
+			mimeType: "application/json",
+			body:     `{"user":{"userId":"hong","password":"p@ss"},"items":[{"token":"t0k"}],"lesson":"11-218"}`,
+			leaks:    []string{"hong", "p@ss", "t0k"},
+			keeps:    []string{`"lesson":"11-218"`},
+		},
+		{
+			name:     "형식을 알 수 없는 본문",
+			mimeType: "text/plain",
+			body:     "login_pwd: p@ss",
+			leaks:    []string{"p@ss"},
+		},
+		{
+			name:     "강습 id 는 그대로",
+			mimeType: "application/json",
+			body:     `{"id":"11-218","lessonId":"218","author":"관리자"}`,
+			keeps:    []string{`"id":"11-218"`, `"lessonId":"218"`, `"author":"관리자"`},
+		},
+		{
+			name:     "민감하지 않은 본문",
+			mimeType: "text/plain",
+			body:     "groupSeq=11",
+			keeps:    []string{"groupSeq=11"},
+		},
+		{
+			name:     "깨진 JSON",
+			mimeType: "application/json",
+			body:     `{"password":"p@ss"`,
+			leaks:    []string{"p@ss"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maskPostData(tt.mimeType, tt.body)
+			for _, s := range tt.leaks {
+				if strings.Contains(got, s) {
+					t.Errorf("maskPostData leaked %q: %s", s, got)
+				}
+			}
+			for _, s := range tt.keeps {
+				if !strings.Contains(got, s) {
+					t.Errorf("maskPostData dropped %q: %s", s, got)
+				}
+			}
+		})
+	}
+
+	var v map[string]any
+	if err := json.Unmarshal([]byte(maskPostData("application/json", `{"login_pwd":"x"}`)), &v); err != nil || v["login_pwd"] != harMask {
+		t.Errorf("masked JSON = %v (%v), want login_pwd masked", v, err)
+	}
+}
+
+func TestHarHeadersMasksCookies(t *testing.T) {
+	headers := proto.NetworkHeaders{
+		"Cookie":        gson.New("JSESSIONID=abc"),
+		"Authorization": gson.New("Bearer x"),
+		"Referer":       gson.New("https://www.auc.or.kr/"),
+	}
+	want := []harNameVal{
+		{Name: "Authorization", Value: harMask},
+		{Name: "Cookie", Value: harMask},
+		{Name: "Referer", Value: "https://www.auc.or.kr/"},
+	}
+	if got := harHeaders(headers); !reflect.DeepEqual(got, want) {
+		t.Errorf("harHeaders = %v, want %v", got, want)
+	}
+}
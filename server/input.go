@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// remoteInput 은 화면에서 보낸 사용자 입력 하나입니다.
+//
+// 좌표는 Coords 가 "page" 면 전체 페이지 스크린샷 기준, "viewport" 면 실시간 화면(현재 보이는 영역) 기준입니다.
+type remoteInput struct {
+	Type       string  `json:"type"`
+	X          float64 `json:"x"`
+	Y          float64 `json:"y"`
+	Coords     string  `json:"coords"`
+	Button     string  `json:"button"`
+	ClickCount int     `json:"clickCount"`
+	Text       string  `json:"text"`
+	Key        string  `json:"key"`
+	DeltaX     float64 `json:"deltaX"`
+	DeltaY     float64 `json:"deltaY"`
+}
+
+// remoteKeys 는 화면에서 보낼 수 있는 특수 키입니다. 일반 문자는 type 으로 보냅니다.
+var remoteKeys = map[string]input.Key{
+	"Enter":      input.Enter,
+	"Tab":        input.Tab,
+	"Escape":     input.Escape,
+	"Backspace":  input.Backspace,
+	"Delete":     input.Delete,
+	"Space":      input.Space,
+	"ArrowUp":    input.ArrowUp,
+	"ArrowDown":  input.ArrowDown,
+	"ArrowLeft":  input.ArrowLeft,
+	"ArrowRight": input.ArrowRight,
+	"PageUp":     input.PageUp,
+	"PageDown":   input.PageDown,
+	"Home":       input.Home,
+	"End":        input.End,
+}
+
+var errBadInput = errors.New("잘못된 입력입니다")
+
+// toViewport 는 페이지 기준 좌표를 현재 보이는 영역 기준으로 바꿉니다.
+// 좌표가 보이는 영역 밖이면 그 위치가 가운데 오도록 먼저 스크롤합니다.
+func toViewport(page *rod.Page, x, y float64) (proto.Point, error) {
+	res, err := page.Eval(`(x, y) => {
+		const w = window.innerWidth, h = window.innerHeight;
+		if (x < scrollX || x >= scrollX + w || y < scrollY || y >= scrollY + h) {
+			window.scrollTo(x - w / 2, y - h / 2);
+		}
+		return { x: x - scrollX, y: y - scrollY };
+	}`, x, y)
+	if err != nil {
+		return proto.Point{}, err
+	}
+
+	var pt proto.Point
+	if err := res.Value.Unmarshal(&pt); err != nil {
+		return proto.Point{}, err
+	}
+	return pt, nil
+}
+
+func (in remoteInput) point(page *rod.Page) (proto.Point, error) {
+	if in.X < 0 || in.Y < 0 {
+		return proto.Point{}, fmt.Errorf("%w: 좌표는 0 이상이어야 합니다", errBadInput)
+	}
+	if in.Coords == "viewport" {
+		return proto.Point{X: in.X, Y: in.Y}, nil
+	}
+	return toViewport(page, in.X, in.Y)
+}
+
+// dispatchInput 은 입력을 실제 마우스/키보드 이벤트로 페이지에 보냅니다.
+func dispatchInput(page *rod.Page, in remoteInput) (string, error) {
+	switch in.Type {
+	case "click":
+		pt, err := in.point(page)
+		if err != nil {
+			return "", err
+		}
+		button := proto.InputMouseButtonLeft
+		if in.Button == "right" {
+			button = proto.InputMouseButtonRight
+		}
+		if err := page.Mouse.MoveTo(pt); err != nil {
+			return "", err
+		}
+		if err := page.Mouse.Click(button, min(max(in.ClickCount, 1), 3)); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("화면 (%.0f, %.0f) 위치를 클릭했습니다.", in.X, in.Y), nil
+
+	case "type":
+		if in.Text == "" {
+			return "", fmt.Errorf("%w: 입력할 글자가 없습니다", errBadInput)
+		}
+		// 한글 등 키 배열에 없는 글자도 들어가도록 IME 입력처럼 넣습니다.
+		if err := page.InsertText(in.Text); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("글자 %d자를 입력했습니다.", len([]rune(in.Text))), nil
+
+	case "key":
+		key, ok := remoteKeys[in.Key]
+		if !ok {
+			return "", fmt.Errorf("%w: 지원하지 않는 키 %q", errBadInput, in.Key)
+		}
+		if err := page.Keyboard.Type(key); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s 키를 눌렀습니다.", in.Key), nil
+
+	case "scroll":
+		if in.DeltaX == 0 && in.DeltaY == 0 {
+			return "", fmt.Errorf("%w: 스크롤 거리가 없습니다", errBadInput)
+		}
+		if in.Coords != "" {
+			pt, err := in.point(page)
+			if err != nil {
+				return "", err
+			}
+			if err := page.Mouse.MoveTo(pt); err != nil {
+				return "", err
+			}
+		}
+		if err := page.Mouse.Scroll(in.DeltaX, in.DeltaY, 1); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("화면을 (%.0f, %.0f)만큼 스크롤했습니다.", in.DeltaX, in.DeltaY), nil
+	}
+
+	return "", fmt.Errorf("%w: 알 수 없는 입력 종류 %q", errBadInput, in.Type)
+}
+
+// Input 은 화면에서 보낸 클릭/입력/키/스크롤을 세션 페이지에 실제 입력으로 전달합니다.
+// 자동화가 처리하지 못한 화면을 손으로 넘긴 뒤 이어서 자동 작업을 실행할 수 있습니다.
+func Input(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	defer r.Body.Close()
+	var in remoteInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		http.Error(w, "요청 본문 파싱에 실패했습니다.", http.StatusBadRequest)
+		return
+	}
+	in.Type = strings.TrimSpace(in.Type)
+
+	// 자동 작업이 페이지를 쓰는 중이면 입력이 섞이지 않도록 오래 기다리지 않고 거절합니다.
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	page, done, err := session.begin(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			http.Error(w, "다른 작업이 진행 중입니다. 취소하거나 끝난 뒤 다시 시도해주세요.", http.StatusConflict)
+			return
+		}
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	message, err := dispatchInput(page, in)
+	if err != nil {
+		if errors.Is(err, errBadInput) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		session.failStep(w, &stepError{Step: "수동 입력", Err: err})
+		return
+	}
+
+	session.pushInfo("[수동] " + message)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(message))
+}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"squash-helper/mock"
+)
+
+// TestMockSiteFlow 는 모의 사이트를 상대로 실행 → 로그인 → 강습 목록 이동 → 신청 → 신청내역 조회/취소를 차례로 실행합니다.
+// Chromium 이 없는 환경에서는 건너뜁니다.
+func TestMockSiteFlow(t *testing.T) {
+	if testing.Short() {
+		t.Skip("브라우저 통합 테스트는 -short 에서 건너뜁니다.")
+	}
+	if _, err := findBrowserBinary(); err != nil {
+		t.Skipf("Chromium 이 없어 건너뜁니다: %v", err)
+	}
+
+	t.Setenv("MOCK_WAIT_SECONDS", "0")
+	t.Setenv("MOCK_OPEN_AT", "")
+	site := httptest.NewServer(mock.Handler())
+	defer site.Close()
+
+	t.Setenv("AUC_BASE_URL", site.URL)
+	t.Setenv("AUC_SSO_URL", site.URL+"/sso/")
+	useSites(t, newAUCHogye())
+
+	for _, load := range []func() error{loadProfiles, loadDialogRules, loadSelectors, loadWorkflows} {
+		if err := load(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prevLogins := logins
+	logins = nil
+	t.Cleanup(func() { logins = prevLogins })
+	t.Cleanup(closeSharedBrowser)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/launch", Launch)
+	mux.HandleFunc("/login", Login)
+	mux.HandleFunc("/move", Move)
+	mux.HandleFunc("/action", Action)
+	mux.HandleFunc("/registrations", Registrations)
+	mux.HandleFunc("/registrations/cancel", CancelRegistration)
+	mux.HandleFunc("/close", Close)
+	app := httptest.NewServer(mux)
+	defer app.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &testClient{t: t, base: app.URL, http: &http.Client{Jar: jar}}
+	defer c.call(http.MethodGet, "/close", "")
+
+	c.ok(http.MethodGet, "/launch", "")
+	c.ok(http.MethodPost, "/login", `{"id":"mock","password":"mock"}`)
+	c.ok(http.MethodGet, "/move", "")
+
+	var report workflowReport
+	c.decode(c.ok(http.MethodGet, "/action?profile=mon-wed&step=all", ""), &report)
+	if !report.OK || report.Outcome == nil || report.Outcome.Result != outcomeSuccess {
+		t.Fatalf("action report = %+v, want ok with success outcome", report)
+	}
+
+	var regs []registration
+	c.decode(c.ok(http.MethodGet, "/registrations", ""), &regs)
+	if len(regs) != 1 {
+		t.Fatalf("registrations = %+v, want one", regs)
+	}
+	reg := regs[0]
+	if !strings.Contains(reg.Lesson, "20:00 - 21:00") || reg.Status != "결제대기" || reg.PaymentDeadline == "" || !reg.Cancelable {
+		t.Fatalf("registration = %+v", reg)
+	}
+
+	body, err := json.Marshal(map[string]string{"id": reg.ID, "confirm": reg.Lesson})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ok(http.MethodPost, "/registrations/cancel", string(body))
+
+	c.decode(c.ok(http.MethodGet, "/registrations", ""), &regs)
+	if len(regs) != 1 || regs[0].Status != "취소" || regs[0].Cancelable {
+		t.Fatalf("registrations after cancel = %+v, want one canceled", regs)
+	}
+}
+
+// useSites 는 테스트 동안 사이트 어댑터 목록과 기본 사이트를 바꿉니다.
+func useSites(t *testing.T, list ...siteAdapter) {
+	t.Helper()
+	prevSites, prevDefault := sites, defaultSite
+	sites, defaultSite = list, list[0]
+	t.Cleanup(func() { sites, defaultSite = prevSites, prevDefault })
+}
+
+func closeSharedBrowser() {
+	sharedBrowserMu.Lock()
+	defer sharedBrowserMu.Unlock()
+	if sharedBrowser != nil {
+		_ = sharedBrowser.Close()
+		sharedBrowser = nil
+	}
+}
+
+type testClient struct {
+	t    *testing.T
+	base string
+	http *http.Client
+}
+
+func (c *testClient) call(method, path, body string) (int, string) {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.base+path, strings.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	return res.StatusCode, string(data)
+}
+
+// ok 는 요청이 200 으로 끝났는지 확인하고 응답 본문을 돌려줍니다.
+func (c *testClient) ok(method, path, body string) string {
+	c.t.Helper()
+	code, text := c.call(method, path, body)
+	if code != http.StatusOK {
+		c.t.Fatalf("%s %s = %d %s", method, path, code, strings.TrimSpace(text))
+	}
+	return text
+}
+
+func (c *testClient) decode(text string, v any) {
+	c.t.Helper()
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		c.t.Fatalf("decode %q: %v", text, err)
+	}
+}
@@ -1,78 +1,60 @@
 package server
 
 import (
-	"fmt"
+	"errors"
 	"log"
 	"net/http"
-	"os"
-	"os/exec"
+	"strings"
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/launcher"
-	"github.com/go-rod/rod/lib/proto"
-	"github.com/go-rod/stealth"
 )
 
+// Launch 는 새 세션을 만들고 로그인 페이지까지 이동합니다. ?site= 로 사이트 어댑터를 고르며 없으면 기본 사이트입니다.
 func Launch(w http.ResponseWriter, r *http.Request) {
+	site := defaultSite
+	if id := strings.TrimSpace(r.URL.Query().Get("site")); id != "" {
+		var ok bool
+		if site, ok = findSite(id); !ok {
+			http.Error(w, "알 수 없는 사이트입니다.", http.StatusBadRequest)
+			return
+		}
+	}
+
 	if sessionID, _, ok := getSessionFromRequest(r); ok {
 		cleanupSession(sessionID)
 	}
 
-	bin, err := findBrowserBinary()
-	if err != nil {
-		log.Printf("browser launch skipped: %v", err)
-		http.Error(w, "브라우저 실행 파일을 찾지 못했습니다. 배포 이미지에 chromium이 포함되어 있는지 확인해주세요.", http.StatusInternalServerError)
-		return
-	}
+	var (
+		browser *rod.Browser
+		page    *rod.Page
+		pp      *pooledPage
+		pooled  bool
+	)
 
-	l := launcher.New().
-		Leakless(false).
-		NoSandbox(true).
-		HeadlessNew(true).
-		// GPU 경로 제거
-		Append("--disable-gpu").
-		// 소프트웨어 GL까지 차단 → CPU 낭비↓
-		Append("--disable-software-rasterizer").
-		// 첫 실행 체크 제거
-		Append("--no-first-run").
-		Append("--no-default-browser-check").
-		// 대기열 유지에 중요 (타이머/렌더러 절전 방지)
-		Append("--disable-background-timer-throttling").
-		Append("--disable-renderer-backgrounding").
-		Append("--disable-backgrounding-occluded-windows").
-		// 창 사이즈 설정
-		Set("window-size", "1280,800").
-		Bin(bin)
-
-	u, err := l.Launch()
-	if err != nil {
-		log.Printf("browser launch failed with %q: %v", bin, err)
-		http.Error(w, "브라우저 실행에 실패했습니다. 서버 로그를 확인해주세요.", http.StatusInternalServerError)
-		return
+	// 대기 페이지는 기본 사이트 메인 페이지로 열어 두므로 기본 사이트일 때만 씁니다.
+	if site == defaultSite {
+		pp, pooled = pool.take()
 	}
-
-	browser := rod.New().ControlURL(u)
-	if err := browser.Connect(); err != nil {
-		log.Printf("browser connect failed: %v", err)
-		http.Error(w, "브라우저 연결에 실패했습니다. 서버 로그를 확인해주세요.", http.StatusInternalServerError)
-		return
-	}
-
-	page, err := stealth.Page(browser)
-	if err != nil {
-		_ = browser.Close()
-		log.Printf("stealth page creation failed: %v", err)
-		http.Error(w, "브라우저 페이지 초기화에 실패했습니다. 서버 로그를 확인해주세요.", http.StatusInternalServerError)
-		return
+	if pooled {
+		// 메인 페이지까지 미리 열어 둔 대기 페이지를 그대로 사용합니다.
+		browser, page = pp.browser, pp.page
+	} else {
+		var err error
+		browser, page, err = newIncognitoPage()
+		if err != nil {
+			var be *browserError
+			if errors.As(err, &be) {
+				http.Error(w, be.userMessage, http.StatusInternalServerError)
+				return
+			}
+			log.Printf("browser session creation failed: %v", err)
+			http.Error(w, "브라우저 실행에 실패했습니다. 서버 로그를 확인해주세요.", http.StatusInternalServerError)
+			return
+		}
 	}
 
-	page.MustNavigate("https://www.auc.or.kr/hogye/main/view")
-
-	session := &userSession{
-		browser: browser,
-		page:    page,
-	}
+	session := newUserSession(browser, page, site)
 	sessionID, err := registerSession(session)
 	if err != nil {
 		_ = browser.Close()
@@ -82,76 +64,44 @@ func Launch(w http.ResponseWriter, r *http.Request) {
 
 	setSessionCookie(w, sessionID)
 
-	if f, ok := w.(http.Flusher); ok {
-		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-		w.WriteHeader(http.StatusOK)
-		f.Flush()
+	// 대화상자는 요청이 끝난 뒤에도 처리해야 하므로 요청 컨텍스트가 없는 페이지로 시작합니다.
+	session.startDialogs(page)
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
 	}
+	defer done()
 
-	session.mu.Lock()
-
-	defer session.mu.Unlock()
-
-	page.MustWaitLoad()
-
-	page.MustNavigate("https://www.auc.or.kr/sign/in/base/user")
-
-	go handleLoginDialogs(page)
-
-	page.MustWaitLoad()
-
-	removeWaitPage(page)
-
-	page.MustElement(".total-loginN__btn").MustClick()
-
-	page.MustWaitLoad()
-
-	removeWaitPage(page)
-
-	w.Write([]byte("로그인 페이지 진입 완료"))
-}
-
-func findBrowserBinary() (string, error) {
-	candidates := []string{
-		os.Getenv("ROD_BROWSER_BIN"),
-		os.Getenv("BROWSER_BIN"),
-		"/usr/bin/chromium",
-		"/usr/bin/chromium-browser",
+	// 메인 페이지 이동도 작업 잠금 안에서 해야 제한 시간과 /cancel 이 적용됩니다.
+	if err := session.step("메인 페이지를 불러오는 중입니다.", func() error {
+		if pooled {
+			return waitLoad(page)
+		}
+		return navigate(page, site.MainURL())
+	}); err != nil {
+		session.failStep(w, err)
+		return
 	}
 
-	if found, ok := launcher.LookPath(); ok {
-		candidates = append(candidates, found)
+	// 저장된 로그인이 아직 유효하면 로그인 페이지를 건너뜁니다.
+	if session.restoreLogin(r, browser, page) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("저장된 로그인으로 복원 완료"))
+		return
 	}
 
-	seen := map[string]struct{}{}
-	for _, candidate := range candidates {
-		if candidate == "" {
-			continue
-		}
-		if _, exists := seen[candidate]; exists {
-			continue
-		}
-		seen[candidate] = struct{}{}
-
-		path, err := exec.LookPath(candidate)
-		if err == nil {
-			return path, nil
-		}
+	session.setDialogStage(dialogStageLogin)
+	if err := session.runSiteSteps(page, site.OpenLogin(), func() {
+		session.handleWaitPage(page)
+	}); err != nil {
+		session.failStep(w, err)
+		return
 	}
 
-	return "", fmt.Errorf("no chromium binary found in candidates: %v", candidates)
-}
-
-func handleLoginDialogs(page *rod.Page) {
-	// 첫 번째 confirm → 취소
-	w1, h1 := page.HandleDialog()
-	w1()
-	_ = h1(&proto.PageHandleJavaScriptDialog{Accept: false})
-
-	// 두 번째 confirm → 확인
-	w2, h2 := page.HandleDialog()
-	w2()
-	_ = h2(&proto.PageHandleJavaScriptDialog{Accept: true})
+	session.pushInfo("로그인 페이지 진입을 완료했습니다.")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("로그인 페이지 진입 완료"))
 }
 
 func Close(w http.ResponseWriter, r *http.Request) {
@@ -171,14 +121,19 @@ func Refresh(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
 
-	page := session.page
-	session.pushInfo("브라우저 새로고침을 요청했습니다.")
-	page.MustReload()
-	// 페이지 진입 대기
-	page.MustWaitLoad()
+	if err := session.step("브라우저 새로고침을 요청했습니다.", func() error {
+		return reload(page)
+	}); err != nil {
+		session.failStep(w, err)
+		return
+	}
 	session.pushInfo("브라우저 새로고침이 완료되었습니다.")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("브라우저 새로고침 완료"))
@@ -190,25 +145,38 @@ func RemoveWaiting(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.mu.Lock()
-	defer session.mu.Unlock()
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	if r.URL.Query().Get("mode") == waitModeWait {
+		session.pushInfo("사용자 요청으로 대기열이 해소될 때까지 기다립니다.")
+		session.handleWaitPageWithMode(page, waitModeWait)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("대기열 대기 완료"))
+		return
+	}
 
-	page := session.page
 	session.pushInfo("사용자 요청으로 대기열을 제거합니다.")
-	removeWaitPage(page)
+	session.handleWaitPageWithMode(page, waitModeForce)
 
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("대기열 제거 완료"))
 }
 
-func removeWaitPage(page *rod.Page) {
+func removeWaitPage(page *rod.Page, selector string) {
 	// 대기열 제거 (삭제)
-	if el, _ := page.Timeout(1 * time.Second).Element("#waitPage"); el != nil {
+	timed := page.Timeout(1 * time.Second)
+	defer timed.CancelTimeout()
+	if el, _ := timed.Element(selector); el != nil {
 		_ = el.Remove()
 	}
 
 	// // 대기열 제거 (숨김)
-	// if el, _ := page.Timeout(1 * time.Second).Element("#waitPage"); el != nil {
+	// if el, _ := page.Timeout(1 * time.Second).Element(selector); el != nil {
 	// 	_, _ = el.Eval(`this.style.display = "none"`)
 	// }
 }
@@ -0,0 +1,263 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// lesson 은 강습 목록의 신청 버튼 하나를 구조화한 결과입니다.
+//
+// <a href="#" onclick="insertOrderSeq('11','218','주2일(화,목)','03','주2일(화,목)','11:00 - 12:30','배드민턴','임미정');" class="common_btn regist">신청</a>
+type lesson struct {
+	ID           string `json:"id"`
+	GroupSeq     string `json:"groupSeq"`
+	LessonSeq    string `json:"lessonSeq"`
+	EntranceType string `json:"entranceType"`
+	EntranceCode string `json:"entranceCode"`
+	DayPattern   string `json:"dayPattern"`
+	TimeRange    string `json:"timeRange"`
+	Sport        string `json:"sport"`
+	Instructor   string `json:"instructor"`
+	ButtonText   string `json:"buttonText"`
+	Available    bool   `json:"available"`
+	Registered   int    `json:"registered"`
+	Capacity     int    `json:"capacity"`
+	Remaining    int    `json:"remaining"`
+}
+
+// lessonButtonSpec 은 신청 버튼을 찾는 선택자와 신청 가능한 버튼의 문구입니다.
+// SeatsHeader 는 "신청인원/정원" 열의 머리글에 들어 있는 문구입니다. 비어 있으면 잔여 좌석을 읽지 않습니다.
+type lessonButtonSpec struct {
+	Selector    string
+	ApplyText   string
+	SeatsHeader string
+}
+
+// lessonButton 은 페이지에서 읽어온 신청 버튼의 원본 데이터입니다.
+// Seats 는 같은 행에서 좌석 열에 해당하는 칸의 텍스트입니다.
+type lessonButton struct {
+	HTML  string `json:"html"`
+	Text  string `json:"text"`
+	Row   string `json:"row"`
+	Seats string `json:"seats,omitempty"`
+}
+
+var (
+	insertOrderSeqPattern = regexp.MustCompile(`insertOrderSeq\(((?:\s*'[^']*'\s*,?)*)\)`)
+	quotedArgPattern      = regexp.MustCompile(`'([^']*)'`)
+	// 좌석 칸 전체가 "신청인원/정원" 이어야 합니다. 좌석 칸만 읽으므로 같은 행의 날짜(10/17)는 보지 않습니다.
+	seatsPattern = regexp.MustCompile(`^(\d+)\s*명?\s*/\s*(\d+)\s*명?$`)
+)
+
+// parseLessonButton 은 insertOrderSeq 호출 인자와 버튼/행 텍스트로 lesson을 만듭니다.
+func parseLessonButton(btn lessonButton, index int, applyText string) lesson {
+	l := lesson{
+		ID:         fmt.Sprintf("row-%d", index),
+		ButtonText: strings.TrimSpace(btn.Text),
+		Registered: -1,
+		Capacity:   -1,
+		Remaining:  -1,
+	}
+
+	if m := insertOrderSeqPattern.FindStringSubmatch(btn.HTML); m != nil {
+		var args []string
+		for _, arg := range quotedArgPattern.FindAllStringSubmatch(m[1], -1) {
+			args = append(args, strings.TrimSpace(arg[1]))
+		}
+		fields := []*string{
+			&l.GroupSeq, &l.LessonSeq, &l.EntranceType, &l.EntranceCode,
+			&l.DayPattern, &l.TimeRange, &l.Sport, &l.Instructor,
+		}
+		for i, f := range fields {
+			if i < len(args) {
+				*f = args[i]
+			}
+		}
+		if l.GroupSeq != "" && l.LessonSeq != "" {
+			l.ID = l.GroupSeq + "-" + l.LessonSeq
+		}
+		// "신청마감", "신청완료" 처럼 신청 문구를 포함한 닫힌 버튼이 있으므로 문구 전체가 같아야 신청 가능으로 봅니다.
+		l.Available = l.ButtonText == strings.TrimSpace(applyText)
+	}
+
+	// 좌석 칸의 "신청인원/정원" 표기로 잔여 좌석을 계산합니다.
+	if registered, capacity, ok := parseSeats(btn.Seats); ok {
+		l.Registered = registered
+		l.Capacity = capacity
+		l.Remaining = max(capacity-registered, 0)
+	}
+
+	return l
+}
+
+// parseSeats 는 좌석 칸의 "신청인원/정원" 을 읽습니다.
+func parseSeats(cell string) (registered, capacity int, ok bool) {
+	m := seatsPattern.FindStringSubmatch(strings.TrimSpace(cell))
+	if m == nil {
+		return 0, 0, false
+	}
+	registered, err1 := strconv.Atoi(m[1])
+	capacity, err2 := strconv.Atoi(m[2])
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return registered, capacity, true
+}
+
+// readLessonButtons 는 신청 버튼과 그 행을 읽습니다. seatsHeader 가 있으면 머리글에 그 문구가 든 열의 칸을 좌석 칸으로 봅니다.
+func readLessonButtons(page *rod.Page, selector, seatsHeader string) ([]lessonButton, error) {
+	res, err := page.Eval(`(selector, seatsHeader) => Array.from(document.querySelectorAll(selector)).map((a) => {
+		const row = a.closest("tr, li");
+		let seats = "";
+		if (row && row.cells && seatsHeader) {
+			const table = row.closest("table");
+			const heads = table ? Array.from(table.querySelectorAll("thead th")) : [];
+			const col = heads.findIndex((th) => (th.textContent || "").includes(seatsHeader));
+			if (col >= 0 && row.cells[col]) {
+				seats = (row.cells[col].innerText || "").trim();
+			}
+		}
+		return {
+			html: a.outerHTML,
+			text: (a.textContent || "").trim(),
+			row: row ? (row.innerText || "").trim() : "",
+			seats: seats,
+		};
+	})`, selector, seatsHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var buttons []lessonButton
+	if err := res.Value.Unmarshal(&buttons); err != nil {
+		return nil, err
+	}
+	return buttons, nil
+}
+
+// scrapeLessons 는 현재 강습 목록 페이지를 lesson 목록으로 변환합니다.
+func scrapeLessons(page *rod.Page, spec lessonButtonSpec) ([]lesson, error) {
+	buttons, err := readLessonButtons(page, spec.Selector, spec.SeatsHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	lessons := make([]lesson, 0, len(buttons))
+	for i, btn := range buttons {
+		lessons = append(lessons, parseLessonButton(btn, i, spec.ApplyText))
+	}
+	return lessons, nil
+}
+
+// clickLessonByID 는 scrapeLessons 가 부여한 ID로 신청 버튼을 찾아 클릭합니다.
+func clickLessonByID(page *rod.Page, spec lessonButtonSpec, id string) (bool, error) {
+	buttons, err := readLessonButtons(page, spec.Selector, spec.SeatsHeader)
+	if err != nil {
+		return false, err
+	}
+
+	for i, btn := range buttons {
+		l := parseLessonButton(btn, i, spec.ApplyText)
+		if l.ID != id {
+			continue
+		}
+		if !l.Available {
+			return false, nil
+		}
+
+		res, err := page.Eval(`(selector, index) => {
+			const btn = document.querySelectorAll(selector)[index];
+			if (!btn) return false;
+			btn.click();
+			return true;
+		}`, spec.Selector, i)
+		if err != nil {
+			return false, err
+		}
+		return res.Value.Bool(), nil
+	}
+
+	return false, nil
+}
+
+func Lessons(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	lessons, err := session.site.Lessons(page)
+	if err != nil {
+		log.Printf("강습 목록 수집 실패: %v", err)
+		session.pushError("강습 목록을 읽지 못했습니다.")
+		http.Error(w, "강습 목록을 읽지 못했습니다. 강습 신청 페이지인지 확인해주세요.", http.StatusInternalServerError)
+		return
+	}
+
+	session.pushInfo(fmt.Sprintf("강습 목록 %d건을 읽었습니다.", len(lessons)))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(lessons); err != nil {
+		log.Printf("강습 목록 응답 인코딩 실패: %v", err)
+	}
+}
+
+func ApplyLesson(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		http.Error(w, "강습 ID가 필요합니다.", http.StatusBadRequest)
+		return
+	}
+
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	session.pushInfo(fmt.Sprintf("강습 %s 신청 버튼을 찾습니다.", id))
+	base := captureBaseline(page)
+	clicked, err := session.site.ClickLessonByID(page, id)
+	if err != nil {
+		log.Printf("강습 %s 신청 실패: %v", id, err)
+		session.pushError("강습 신청 버튼 클릭에 실패했습니다.")
+		http.Error(w, "강습 신청 버튼 클릭에 실패했습니다.", http.StatusInternalServerError)
+		return
+	}
+	if !clicked {
+		session.pushError(fmt.Sprintf("강습 %s의 신청 가능한 버튼을 찾지 못했습니다.", id))
+		http.Error(w, "신청 가능한 강습 버튼을 찾지 못했습니다.", http.StatusNotFound)
+		return
+	}
+
+	if err := waitLoad(page); err != nil {
+		session.failStep(w, &stepError{Step: "강습 신청 후 페이지 로딩", Err: err})
+		return
+	}
+	session.pushInfo("강습 시간 선택을 완료했습니다.")
+	writeOutcome(w, "강습 시간 선택 완료", session.detectOutcome(page, base))
+}
@@ -0,0 +1,107 @@
+package server
+
+import "testing"
+
+func TestParseSeats(t *testing.T) {
+	tests := []struct {
+		cell                 string
+		registered, capacity int
+		ok                   bool
+	}{
+		{"12/20", 12, 20, true},
+		{" 3 / 15 ", 3, 15, true},
+		{"20명/20명", 20, 20, true},
+		{"0 명 / 8 명", 0, 8, true},
+		{"", 0, 0, false},
+		{"마감", 0, 0, false},
+		{"2026/10/17", 0, 0, false},
+		{"10/17 ~ 11/30", 0, 0, false},
+		{"신청 12/20", 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		registered, capacity, ok := parseSeats(tt.cell)
+		if ok != tt.ok || registered != tt.registered || capacity != tt.capacity {
+			t.Errorf("parseSeats(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.cell, registered, capacity, ok, tt.registered, tt.capacity, tt.ok)
+		}
+	}
+}
+
+func TestParseLessonButton(t *testing.T) {
+	const html = `<a href="#" onclick="insertOrderSeq('11','218','주2일(화,목)','03','주2일(화,목)','11:00 - 12:30','배드민턴','임미정');" class="common_btn regist">신청</a>`
+
+	tests := []struct {
+		name string
+		btn  lessonButton
+		want lesson
+	}{
+		{
+			name: "신청 가능",
+			btn:  lessonButton{HTML: html, Text: " 신청 ", Row: "배드민턴 10/17 개강 12/20", Seats: "12/20"},
+			want: lesson{
+				ID: "11-218", GroupSeq: "11", LessonSeq: "218", EntranceType: "주2일(화,목)", EntranceCode: "03",
+				DayPattern: "주2일(화,목)", TimeRange: "11:00 - 12:30", Sport: "배드민턴", Instructor: "임미정",
+				ButtonText: "신청", Available: true, Registered: 12, Capacity: 20, Remaining: 8,
+			},
+		},
+		{
+			name: "정원 초과 표기",
+			btn:  lessonButton{HTML: html, Text: "신청", Seats: "22/20"},
+			want: lesson{
+				ID: "11-218", GroupSeq: "11", LessonSeq: "218", EntranceType: "주2일(화,목)", EntranceCode: "03",
+				DayPattern: "주2일(화,목)", TimeRange: "11:00 - 12:30", Sport: "배드민턴", Instructor: "임미정",
+				ButtonText: "신청", Available: true, Registered: 22, Capacity: 20, Remaining: 0,
+			},
+		},
+		{
+			// 좌석 칸을 찾지 못하면 행의 날짜를 좌석으로 읽지 않습니다.
+			name: "좌석 칸 없음",
+			btn:  lessonButton{HTML: html, Text: "마감", Row: "배드민턴 10/17 개강"},
+			want: lesson{
+				ID: "11-218", GroupSeq: "11", LessonSeq: "218", EntranceType: "주2일(화,목)", EntranceCode: "03",
+				DayPattern: "주2일(화,목)", TimeRange: "11:00 - 12:30", Sport: "배드민턴", Instructor: "임미정",
+				ButtonText: "마감", Registered: -1, Capacity: -1, Remaining: -1,
+			},
+		},
+		{
+			name: "신청 문구를 포함한 마감 버튼",
+			btn:  lessonButton{HTML: html, Text: "신청마감", Seats: "20/20"},
+			want: lesson{
+				ID: "11-218", GroupSeq: "11", LessonSeq: "218", EntranceType: "주2일(화,목)", EntranceCode: "03",
+				DayPattern: "주2일(화,목)", TimeRange: "11:00 - 12:30", Sport: "배드민턴", Instructor: "임미정",
+				ButtonText: "신청마감", Registered: 20, Capacity: 20, Remaining: 0,
+			},
+		},
+		{
+			name: "신청 완료 버튼",
+			btn:  lessonButton{HTML: html, Text: " 신청완료 ", Seats: "13/20"},
+			want: lesson{
+				ID: "11-218", GroupSeq: "11", LessonSeq: "218", EntranceType: "주2일(화,목)", EntranceCode: "03",
+				DayPattern: "주2일(화,목)", TimeRange: "11:00 - 12:30", Sport: "배드민턴", Instructor: "임미정",
+				ButtonText: "신청완료", Registered: 13, Capacity: 20, Remaining: 7,
+			},
+		},
+		{
+			name: "insertOrderSeq 없음",
+			btn:  lessonButton{HTML: `<a class="common_btn regist">대기</a>`, Text: "대기", Seats: "20/20"},
+			want: lesson{ID: "row-3", ButtonText: "대기", Registered: 20, Capacity: 20, Remaining: 0},
+		},
+		{
+			name: "인자 일부만 있음",
+			btn:  lessonButton{HTML: `<a onclick="insertOrderSeq('7', '9', '주3일');">신청</a>`, Text: "신청"},
+			want: lesson{
+				ID: "7-9", GroupSeq: "7", LessonSeq: "9", EntranceType: "주3일",
+				ButtonText: "신청", Available: true, Registered: -1, Capacity: -1, Remaining: -1,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLessonButton(tt.btn, 3, "신청"); got != tt.want {
+				t.Errorf("parseLessonButton() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	monitorDefaultInterval = 30 * time.Second
+	monitorMinInterval     = 10 * time.Second
+)
+
+// monitorMaxDuration 이 지나면 좌석 감시를 스스로 끝냅니다. 잊힌 감시가 세션과 브라우저를 계속 붙잡지 않게 합니다.
+var monitorMaxDuration = durationFromEnv("MONITOR_MAX_DURATION", 6*time.Hour)
+
+type monitorStatus struct {
+	LessonIDs  []string          `json:"lessonIds"`
+	Profile    string            `json:"profile,omitempty"`
+	Interval   string            `json:"interval"`
+	Checks     int               `json:"checks"`
+	Skipped    int               `json:"skipped"`
+	ExpiresAt  time.Time         `json:"expiresAt"`
+	LastCheck  time.Time         `json:"lastCheck"`
+	LastError  string            `json:"lastError,omitempty"`
+	LastStates map[string]lesson `json:"lastStates"`
+}
+
+// seatMonitor 는 관심 강습의 신청 가능 여부를 주기적으로 확인합니다.
+type seatMonitor struct {
+	mu       sync.Mutex
+	status   monitorStatus
+	interval time.Duration
+	cancel   context.CancelFunc
+}
+
+func (m *seatMonitor) snapshot() monitorStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	st := m.status
+	st.LessonIDs = append([]string(nil), m.status.LessonIDs...)
+	st.LastStates = make(map[string]lesson, len(m.status.LastStates))
+	for id, l := range m.status.LastStates {
+		st.LastStates[id] = l
+	}
+	return st
+}
+
+func (s *userSession) stopMonitor() bool {
+	if s == nil {
+		return false
+	}
+
+	s.monitorMu.Lock()
+	m := s.monitor
+	s.monitor = nil
+	s.monitorMu.Unlock()
+
+	if m != nil && m.cancel != nil {
+		m.cancel()
+		return true
+	}
+	return false
+}
+
+// expireMonitor 는 최대 감시 시간이 지난 m 을 세션에서 내립니다. 그 사이 새 감시로 바뀌었으면 그대로 둡니다.
+func (s *userSession) expireMonitor(m *seatMonitor) {
+	s.monitorMu.Lock()
+	expired := s.monitor == m
+	if expired {
+		s.monitor = nil
+	}
+	s.monitorMu.Unlock()
+
+	if expired {
+		s.pushStatus("notice", fmt.Sprintf("[감시] 최대 감시 시간(%s)이 지나 좌석 감시를 종료했습니다.", monitorMaxDuration))
+	}
+}
+
+func (s *userSession) hasMonitor() bool {
+	if s == nil {
+		return false
+	}
+
+	s.monitorMu.Lock()
+	defer s.monitorMu.Unlock()
+	return s.monitor != nil
+}
+
+func Monitor(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		session.monitorMu.Lock()
+		m := session.monitor
+		session.monitorMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		var resp any
+		if m != nil {
+			snap := m.snapshot()
+			resp = &snap
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("좌석 감시 상태 응답 인코딩 실패: %v", err)
+		}
+	case http.MethodPost:
+		defer r.Body.Close()
+		var payload struct {
+			LessonIDs       []string `json:"lessonIds"`
+			Profile         string   `json:"profile"`
+			IntervalSeconds int      `json:"intervalSeconds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "요청 본문 파싱에 실패했습니다.", http.StatusBadRequest)
+			return
+		}
+
+		var ids []string
+		for _, id := range payload.LessonIDs {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			http.Error(w, "감시할 강습 ID를 하나 이상 지정해주세요.", http.StatusBadRequest)
+			return
+		}
+
+		var profile *targetProfile
+		if payload.Profile != "" {
+			p, ok := findSiteProfile(payload.Profile, session.site)
+			if !ok {
+				http.Error(w, "알 수 없는 대상 프로필입니다.", http.StatusBadRequest)
+				return
+			}
+			profile = &p
+		}
+
+		interval := monitorDefaultInterval
+		if payload.IntervalSeconds > 0 {
+			interval = max(time.Duration(payload.IntervalSeconds)*time.Second, monitorMinInterval)
+		}
+
+		session.stopMonitor()
+
+		ctx, cancel := context.WithTimeout(context.Background(), monitorMaxDuration)
+		m := &seatMonitor{
+			status: monitorStatus{
+				LessonIDs:  ids,
+				Profile:    payload.Profile,
+				Interval:   interval.String(),
+				ExpiresAt:  time.Now().Add(monitorMaxDuration),
+				LastStates: map[string]lesson{},
+			},
+			interval: interval,
+			cancel:   cancel,
+		}
+
+		session.monitorMu.Lock()
+		session.monitor = m
+		session.monitorMu.Unlock()
+
+		session.pushInfo(fmt.Sprintf("[감시] 강습 %s 좌석 감시를 시작합니다. (%s 간격)", strings.Join(ids, ", "), interval))
+		go session.runMonitor(ctx, m, profile)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("좌석 감시 시작"))
+	case http.MethodDelete:
+		if session.stopMonitor() {
+			session.pushInfo("[감시] 좌석 감시를 중지했습니다.")
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("좌석 감시 중지"))
+	default:
+		http.Error(w, "GET, POST, DELETE 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *userSession) runMonitor(ctx context.Context, m *seatMonitor, profile *targetProfile) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		// 예약 신청이 브라우저를 쓰는 구간에는 새로고침으로 작업 잠금을 잡지 않습니다.
+		// 한 번의 확인은 최대 timeouts.Request 동안 잠금을 쥘 수 있으므로 그만큼 앞서 멈춥니다.
+		if s.scheduleBusy(timeouts.Request) {
+			m.mu.Lock()
+			m.status.Skipped++
+			m.mu.Unlock()
+		} else {
+			s.checkSeats(ctx, m, profile)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				s.expireMonitor(m)
+			}
+			return
+		}
+	}
+}
+
+// checkSeats 는 강습 목록을 다시 읽어 이전 상태와 비교합니다.
+func (s *userSession) checkSeats(ctx context.Context, m *seatMonitor, profile *targetProfile) {
+	lessons, err := s.reloadLessons(ctx, profile)
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status.Checks++
+	m.status.LastCheck = time.Now()
+	if err != nil {
+		m.status.LastError = err.Error()
+		s.pushError(fmt.Sprintf("[감시] 강습 목록을 읽지 못했습니다: %v", err))
+		return
+	}
+	m.status.LastError = ""
+
+	byID := make(map[string]lesson, len(lessons))
+	for _, l := range lessons {
+		byID[l.ID] = l
+	}
+
+	for _, id := range m.status.LessonIDs {
+		cur, found := byID[id]
+		if !found {
+			continue
+		}
+
+		prev, seen := m.status.LastStates[id]
+		m.status.LastStates[id] = cur
+		if !seen {
+			continue
+		}
+
+		opened := !prev.Available && cur.Available
+		seatsFreed := prev.Remaining == 0 && cur.Remaining > 0
+		if opened || seatsFreed {
+			s.pushStatus("notice", fmt.Sprintf("[감시] %s %s %s 강습에 신청 가능한 자리가 생겼습니다! (%s)",
+				id, cur.DayPattern, cur.TimeRange, cur.ButtonText))
+		}
+	}
+}
+
+func (s *userSession) reloadLessons(ctx context.Context, profile *targetProfile) ([]lesson, error) {
+	page, done, err := s.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	if err := reload(page); err != nil {
+		return nil, err
+	}
+	s.handleWaitPage(page)
+
+	if profile != nil {
+		if err := s.chooseArea(page, *profile); err != nil {
+			return nil, err
+		}
+		if err := s.chooseEntrance(page, *profile); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.site.Lessons(page)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScheduleBusy(t *testing.T) {
+	// 사이트 시계가 실제 사이트 대신 테스트 서버와 동기화하도록 합니다.
+	t.Setenv("AUC_BASE_URL", dateServer(t, 0).URL)
+	site := newAUCHogye()
+	t.Cleanup(func() { clockFor(site).stop() })
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		state string
+		open  time.Time
+		want  bool
+	}{
+		{"예약 없음", "", time.Time{}, false},
+		{"사전 이동 한참 전", "waiting", now.Add(time.Hour), false},
+		{"사전 이동 임박", "waiting", now.Add(schedulePrepareLead + 30*time.Second), true},
+		{"사전 이동 중", "preparing", now.Add(time.Hour), true},
+		{"신청 중", "applying", now.Add(-time.Second), true},
+		{"완료", "done", now.Add(-time.Minute), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &userSession{site: site}
+			if tt.state != "" {
+				s.schedule = &applySchedule{status: scheduleStatus{OpenAt: tt.open, State: tt.state}}
+			}
+			if got := s.scheduleBusy(time.Minute); got != tt.want {
+				t.Errorf("scheduleBusy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpireMonitor(t *testing.T) {
+	s := &userSession{status: newStatusBroadcaster(statusConfig)}
+	old := &seatMonitor{}
+	cur := &seatMonitor{}
+
+	s.monitor = cur
+	s.expireMonitor(old)
+	if !s.hasMonitor() {
+		t.Fatal("expireMonitor removed a monitor that replaced the expired one")
+	}
+
+	s.expireMonitor(cur)
+	if s.hasMonitor() {
+		t.Fatal("expireMonitor kept the expired monitor")
+	}
+}
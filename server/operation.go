@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// operationTimeouts 는 브라우저 작업별 제한 시간입니다.
+// TIMEOUT_REQUEST, TIMEOUT_NAVIGATE, TIMEOUT_ELEMENT 환경 변수(예: "90s")로 바꿀 수 있습니다.
+type operationTimeouts struct {
+	// 요청 하나가 브라우저를 점유할 수 있는 최대 시간
+	Request time.Duration
+	// 페이지 이동과 로딩 대기
+	Navigate time.Duration
+	// 요소가 나타날 때까지의 대기
+	Element time.Duration
+}
+
+var timeouts = loadOperationTimeouts()
+
+func loadOperationTimeouts() operationTimeouts {
+	return operationTimeouts{
+		Request:  durationFromEnv("TIMEOUT_REQUEST", 3*time.Minute),
+		Navigate: durationFromEnv("TIMEOUT_NAVIGATE", 30*time.Second),
+		Element:  durationFromEnv("TIMEOUT_ELEMENT", 10*time.Second),
+	}
+}
+
+func durationFromEnv(key string, fallback time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Printf("%s 값 %q을(를) 해석하지 못해 기본값 %s을(를) 사용합니다.", key, value, fallback)
+		return fallback
+	}
+	return d
+}
+
+// begin 은 세션의 브라우저 작업 잠금을 얻고 parent 에서 파생된 컨텍스트가 연결된 페이지를 돌려줍니다.
+// 요청이 끊기거나 /cancel 이 호출되거나 제한 시간이 지나면 진행 중인 브라우저 작업이 중단됩니다.
+// 대기열을 기다리는 방식(wait)이면 대기열이 해소될 때까지 기다릴 수 있도록 제한 시간에 waitPageMaxWait 를 더합니다.
+// 반환된 done 은 반드시 호출해야 잠금이 풀립니다.
+func (s *userSession) begin(parent context.Context) (*rod.Page, func(), error) {
+	limit := timeouts.Request
+	if s.getWaitMode() == waitModeWait {
+		limit += waitPageMaxWait
+	}
+	ctx, cancel := context.WithTimeout(parent, limit)
+
+	select {
+	case s.busy <- struct{}{}:
+	case <-ctx.Done():
+		cancel()
+		return nil, nil, fmt.Errorf("이전 작업이 끝나기를 기다리는 중 요청이 중단되었습니다: %w", ctx.Err())
+	}
+
+	s.opMu.Lock()
+	s.opCancel = cancel
+	s.opMu.Unlock()
+
+	done := func() {
+		s.opMu.Lock()
+		s.opCancel = nil
+		s.opMu.Unlock()
+		cancel()
+		<-s.busy
+	}
+
+	s.mu.Lock()
+	page := s.page
+	s.mu.Unlock()
+
+	if page == nil {
+		done()
+		return nil, nil, errNoPage
+	}
+
+	return page.Context(ctx), done, nil
+}
+
+// cancelOperation 은 진행 중인 브라우저 작업을 중단합니다.
+func (s *userSession) cancelOperation() bool {
+	if s == nil {
+		return false
+	}
+
+	s.opMu.Lock()
+	cancel := s.opCancel
+	s.opMu.Unlock()
+
+	if cancel == nil {
+		return false
+	}
+	cancel()
+	return true
+}
+
+// pause 는 페이지 컨텍스트가 취소되면 즉시 깨어나는 time.Sleep 입니다.
+func pause(page *rod.Page, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-page.GetContext().Done():
+		return page.GetContext().Err()
+	}
+}
+
+func Cancel(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	if !session.cancelOperation() {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("진행 중인 작업이 없습니다."))
+		return
+	}
+
+	session.pushInfo("사용자 요청으로 진행 중인 작업을 취소했습니다.")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("작업 취소 완료"))
+}
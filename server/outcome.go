@@ -0,0 +1,269 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// 신청 결과 분류
+const (
+	outcomeSuccess        = "success"
+	outcomeAlreadyApplied = "already-applied"
+	outcomeFull           = "full"
+	outcomeNotOpen        = "not-open"
+	outcomeSessionExpired = "session-expired"
+	outcomeUnknown        = "unknown"
+)
+
+const (
+	// 신청 버튼 클릭 뒤 사이트 반응을 기다리는 최대 시간
+	outcomeWait = 8 * time.Second
+	// 페이지 문구를 다시 읽는 간격
+	outcomePoll = 300 * time.Millisecond
+)
+
+// applyOutcome 은 신청 버튼을 누른 뒤 사이트가 실제로 어떻게 반응했는지입니다.
+type applyOutcome struct {
+	Result   string   `json:"result"`
+	Message  string   `json:"message"`
+	Evidence string   `json:"evidence,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	Dialogs  []string `json:"dialogs,omitempty"`
+}
+
+// outcomeRule 은 문구나 주소로 결과를 판단하는 규칙입니다. 위에서부터 먼저 맞는 규칙을 씁니다.
+type outcomeRule struct {
+	result  string
+	message string
+	text    *regexp.Regexp
+	url     *regexp.Regexp
+}
+
+var outcomeRules = []outcomeRule{
+	{
+		result:  outcomeSessionExpired,
+		message: "로그인이 만료되었습니다. 다시 로그인해주세요.",
+		text:    regexp.MustCompile(`로그인이\s*필요|다시\s*로그인|세션이\s*만료|로그인\s*후\s*이용`),
+		// 로그인 페이지 주소는 사이트마다 다르므로 classifyOutcome 의 loginPage 로 판단합니다.
+	},
+	{
+		result:  outcomeAlreadyApplied,
+		message: "이미 신청한 강습입니다.",
+		text:    regexp.MustCompile(`이미\s*신청|중복\s*신청|이미\s*등록|신청\s*내역이\s*있`),
+	},
+	{
+		result:  outcomeFull,
+		message: "정원이 마감되었습니다.",
+		text:    regexp.MustCompile(`정원(이|을)?\s*(마감|초과)|인원(이)?\s*(마감|초과)|마감되었|잔여\s*(석|인원)(이)?\s*없`),
+	},
+	{
+		result:  outcomeNotOpen,
+		message: "아직 신청 기간이 아닙니다.",
+		text:    regexp.MustCompile(`신청\s*기간이\s*아닙|접수\s*기간이\s*아닙|신청\s*시작\s*전|접수\s*시작\s*전|오픈\s*전`),
+	},
+	{
+		result:  outcomeSuccess,
+		message: "신청이 완료되었습니다.",
+		text:    regexp.MustCompile(`신청(이)?\s*(완료|접수)되었|신청되었습니다|접수되었습니다|결제\s*(하기|진행|기한)|장바구니에\s*담`),
+		url:     regexp.MustCompile(`/(order|payment|cart)`),
+	},
+}
+
+// classifyOutcome 은 대화상자 문구, 페이지 문구, 현재 주소로 결과를 판단합니다.
+// 대화상자 문구가 가장 직접적인 신호이므로 먼저 봅니다.
+// loginPage 는 사이트 어댑터의 IsLoginPage 이며, 로그인 페이지로 돌아갔으면 로그인 만료로 봅니다.
+func classifyOutcome(dialogs []string, pageText, url string, loginPage func(url string) bool) (applyOutcome, bool) {
+	sources := []struct {
+		name string
+		text string
+	}{
+		{"대화상자", strings.Join(dialogs, "\n")},
+		{"페이지 문구", pageText},
+	}
+
+	for _, src := range sources {
+		if src.text == "" {
+			continue
+		}
+		for _, rule := range outcomeRules {
+			if loc := rule.text.FindStringIndex(src.text); loc != nil {
+				return applyOutcome{
+					Result:   rule.result,
+					Message:  rule.message,
+					Evidence: fmt.Sprintf("%s: %s", src.name, excerpt(src.text, loc[0], loc[1])),
+				}, true
+			}
+		}
+	}
+
+	if url != "" && loginPage != nil && loginPage(url) {
+		return applyOutcome{
+			Result:   outcomeSessionExpired,
+			Message:  outcomeRules[0].message,
+			Evidence: "주소: " + url,
+		}, true
+	}
+	for _, rule := range outcomeRules {
+		if rule.url != nil && rule.url.MatchString(url) {
+			return applyOutcome{
+				Result:   rule.result,
+				Message:  rule.message,
+				Evidence: "주소: " + url,
+			}, true
+		}
+	}
+
+	return applyOutcome{}, false
+}
+
+// excerpt 는 맞은 부분 앞뒤 문구를 조금 붙여 돌려줍니다.
+func excerpt(text string, start, end int) string {
+	const around = 30
+	runes := []rune(text)
+	rs := len([]rune(text[:start]))
+	re := len([]rune(text[:end]))
+	from := max(rs-around, 0)
+	to := min(re+around, len(runes))
+	return strings.Join(strings.Fields(string(runes[from:to])), " ")
+}
+
+func currentURL(page *rod.Page) string {
+	info, err := page.Info()
+	if err != nil {
+		return ""
+	}
+	return info.URL
+}
+
+func readPageText(page *rod.Page) string {
+	res, err := page.Eval(`() => (document.body ? document.body.innerText : "").slice(0, 20000)`)
+	if err != nil {
+		return ""
+	}
+	return res.Value.Str()
+}
+
+// outcomeBaseline 은 신청 버튼을 누르기 직전의 페이지 상태입니다.
+// 강습 목록에는 원래 "마감" 같은 문구가 있으므로 클릭 뒤 새로 나타난 줄만 봅니다.
+type outcomeBaseline struct {
+	at    time.Time
+	url   string
+	lines map[string]struct{}
+}
+
+func captureBaseline(page *rod.Page) outcomeBaseline {
+	base := outcomeBaseline{
+		at:    time.Now(),
+		url:   currentURL(page),
+		lines: map[string]struct{}{},
+	}
+	for _, line := range strings.Split(readPageText(page), "\n") {
+		base.lines[strings.TrimSpace(line)] = struct{}{}
+	}
+	return base
+}
+
+// newText 는 기준 상태에 없던 줄만 남깁니다.
+func (b outcomeBaseline) newText(text string) string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if _, seen := b.lines[line]; !seen {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// detectOutcome 은 신청 버튼을 누른 뒤의 대화상자, 주소 이동, 새로 나타난 페이지 문구를 지켜보고 결과를 분류합니다.
+// outcomeWait 안에 판단하지 못하면 unknown 을 돌려줍니다. 결과는 상태 스트림에도 남깁니다.
+func (s *userSession) detectOutcome(page *rod.Page, base outcomeBaseline) applyOutcome {
+	deadline := time.Now().Add(outcomeWait)
+
+	var (
+		out     applyOutcome
+		dialogs []string
+		url     string
+	)
+	for {
+		dialogs = s.dialogsSince(base.at)
+		url = currentURL(page)
+
+		var ok bool
+		if out, ok = classifyOutcome(dialogs, base.newText(readPageText(page)), url, s.site.IsLoginPage); ok {
+			break
+		}
+		if !time.Now().Before(deadline) || pause(page, outcomePoll) != nil {
+			out = applyOutcome{
+				Result:  outcomeUnknown,
+				Message: "사이트 반응으로 신청 결과를 판단하지 못했습니다. 화면을 확인해주세요.",
+			}
+			if url != "" && url != base.url {
+				out.Evidence = "이동한 주소: " + url
+			}
+			break
+		}
+	}
+
+	out.URL = url
+	out.Dialogs = dialogs
+	s.pushOutcome(out)
+	return out
+}
+
+func (s *userSession) pushOutcome(out applyOutcome) {
+	level := "error"
+	if out.Result == outcomeSuccess || out.Result == outcomeUnknown {
+		level = "info"
+	}
+
+	message := "[결과] " + out.Message
+	if out.Evidence != "" {
+		message += " (" + out.Evidence + ")"
+	}
+	s.pushStatus(level, message)
+}
+
+// dialogsSince 는 since 이후 세션에서 처리한 대화상자 문구입니다.
+func (s *userSession) dialogsSince(since time.Time) []string {
+	s.dialogMu.Lock()
+	d := s.dialogs
+	s.dialogMu.Unlock()
+	if d == nil {
+		return nil
+	}
+
+	var out []string
+	for _, rec := range d.history() {
+		if !rec.At.Before(since) {
+			out = append(out, rec.Message)
+		}
+	}
+	return out
+}
+
+// writeOutcome 은 신청 결과를 JSON 으로 응답합니다. 요청 자체는 끝났으므로 결과와 관계없이 200 입니다.
+func writeOutcome(w http.ResponseWriter, message string, out applyOutcome) {
+	resp := struct {
+		Message string       `json:"message"`
+		Outcome applyOutcome `json:"outcome"`
+	}{
+		Message: message,
+		Outcome: out,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("신청 결과 응답 인코딩 실패: %v", err)
+	}
+}
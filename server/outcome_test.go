@@ -0,0 +1,131 @@
+package server
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyOutcome(t *testing.T) {
+	site := aucHogye{origin: "http://127.0.0.1:9090", sso: "http://127.0.0.1:9090/sso/"}
+	const lessonList = "http://127.0.0.1:9090/reservation/program/lesson/list"
+
+	tests := []struct {
+		name     string
+		dialogs  []string
+		text     string
+		url      string
+		result   string
+		evidence string
+	}{
+		{
+			name:     "대화상자: 신청 완료",
+			dialogs:  []string{"강습을 신청하시겠습니까?", "신청이 완료되었습니다."},
+			url:      lessonList,
+			result:   outcomeSuccess,
+			evidence: "대화상자",
+		},
+		{
+			name:     "대화상자가 페이지 문구보다 우선",
+			dialogs:  []string{"이미 신청한 강습입니다."},
+			text:     "정원이 마감되었습니다.",
+			url:      lessonList,
+			result:   outcomeAlreadyApplied,
+			evidence: "대화상자",
+		},
+		{
+			name:     "페이지 문구: 정원 마감",
+			text:     "죄송합니다. 정원이 초과되어 신청할 수 없습니다.",
+			url:      lessonList,
+			result:   outcomeFull,
+			evidence: "페이지 문구",
+		},
+		{
+			name:     "페이지 문구: 신청 기간 전",
+			text:     "현재는 신청 기간이 아닙니다.",
+			url:      lessonList,
+			result:   outcomeNotOpen,
+			evidence: "페이지 문구",
+		},
+		{
+			name:     "페이지 문구: 결제 진행",
+			text:     "결제 기한 2026-10-20 까지 결제하기",
+			url:      lessonList,
+			result:   outcomeSuccess,
+			evidence: "페이지 문구",
+		},
+		{
+			name:     "문구: 로그인 만료",
+			dialogs:  []string{"로그인 후 이용해주세요."},
+			url:      lessonList,
+			result:   outcomeSessionExpired,
+			evidence: "대화상자",
+		},
+		{
+			name:     "주소: 사이트 로그인 페이지",
+			url:      "http://127.0.0.1:9090/sign/in/base/user",
+			result:   outcomeSessionExpired,
+			evidence: "주소",
+		},
+		{
+			name:     "주소: 통합 로그인 페이지",
+			url:      "http://127.0.0.1:9090/sso/login",
+			result:   outcomeSessionExpired,
+			evidence: "주소",
+		},
+		{
+			// 다른 사이트의 로그인 주소는 이 세션의 로그인 페이지가 아닙니다.
+			name: "주소: 다른 사이트의 통합 로그인",
+			url:  "https://newsso.anyang.go.kr/login",
+		},
+		{
+			name:     "주소: 결제 페이지",
+			url:      "http://127.0.0.1:9090/payment/view",
+			result:   outcomeSuccess,
+			evidence: "주소",
+		},
+		{
+			name: "판단 불가",
+			text: "강습 목록",
+			url:  lessonList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, ok := classifyOutcome(tt.dialogs, tt.text, tt.url, site.IsLoginPage)
+			if tt.result == "" {
+				if ok {
+					t.Fatalf("classifyOutcome = %+v, want no match", out)
+				}
+				return
+			}
+			if !ok || out.Result != tt.result {
+				t.Fatalf("classifyOutcome = %+v (%v), want %s", out, ok, tt.result)
+			}
+			if !strings.HasPrefix(out.Evidence, tt.evidence) {
+				t.Errorf("Evidence = %q, want prefix %q", out.Evidence, tt.evidence)
+			}
+			if out.Message == "" {
+				t.Error("Message is empty")
+			}
+		})
+	}
+}
+
+func TestClassifyOutcomeWithoutLoginPage(t *testing.T) {
+	if out, ok := classifyOutcome(nil, "", "https://www.auc.or.kr/sign/in/base/user", nil); ok {
+		t.Errorf("classifyOutcome without loginPage = %+v, want no match", out)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	text := strings.Repeat("가", 50) + " 정원이  마감되었습니다 " + strings.Repeat("나", 50)
+	loc := outcomeRules[2].text.FindStringIndex(text)
+	if loc == nil {
+		t.Fatal("full rule did not match")
+	}
+	got := excerpt(text, loc[0], loc[1])
+	if !strings.Contains(got, "정원이 마감") || len([]rune(got)) > 80 {
+		t.Errorf("excerpt = %q", got)
+	}
+}
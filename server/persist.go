@@ -0,0 +1,367 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	loginCookieName = "squash-helper-login"
+	loginStateTTL   = 30 * 24 * time.Hour
+)
+
+// loginState 는 로그인 이후의 쿠키와 스토리지를 재시작 뒤 복원하기 위해 저장하는 내용입니다.
+// Site 는 저장할 때 쓴 사이트 어댑터이며 다른 사이트로 실행한 세션에는 복원하지 않습니다.
+type loginState struct {
+	Site           string                 `json:"site"`
+	Cookies        []*proto.NetworkCookie `json:"cookies"`
+	LocalStorage   map[string]string      `json:"localStorage"`
+	SessionStorage map[string]string      `json:"sessionStorage"`
+	SavedAt        time.Time              `json:"savedAt"`
+}
+
+// loginStore 는 loginState 를 AES-GCM 으로 암호화해 디스크에 저장합니다.
+// LOGIN_STORE_KEY 가 없으면 비활성화되고, LOGIN_STORE_DIR 로 저장 위치를 바꿀 수 있습니다.
+type loginStore struct {
+	dir  string
+	aead cipher.AEAD
+}
+
+var (
+	logins           = newLoginStoreFromEnv()
+	loginTokenFormat = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+func newLoginStoreFromEnv() *loginStore {
+	secret := os.Getenv("LOGIN_STORE_KEY")
+	if secret == "" {
+		log.Printf("LOGIN_STORE_KEY가 없어 로그인 상태 저장을 사용하지 않습니다.")
+		return nil
+	}
+
+	dir := strings.TrimSpace(os.Getenv("LOGIN_STORE_DIR"))
+	if dir == "" {
+		dir = filepath.Join("data", "logins")
+	}
+
+	store, err := newLoginStore(dir, secret)
+	if err != nil {
+		log.Printf("로그인 상태 저장소 초기화 실패: %v", err)
+		return nil
+	}
+	return store
+}
+
+func newLoginStore(dir, secret string) (*loginStore, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &loginStore{dir: dir, aead: aead}, nil
+}
+
+func (st *loginStore) path(token string) (string, error) {
+	if !loginTokenFormat.MatchString(token) {
+		return "", errors.New("잘못된 로그인 토큰입니다")
+	}
+	return filepath.Join(st.dir, token+".bin"), nil
+}
+
+func (st *loginStore) save(token string, state *loginState) error {
+	path, err := st.path(token)
+	if err != nil {
+		return err
+	}
+
+	plain, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, st.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := st.aead.Seal(nonce, nonce, plain, []byte(token))
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, sealed, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (st *loginStore) load(token string) (*loginState, error) {
+	path, err := st.path(token)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	n := st.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, errors.New("저장된 로그인 정보가 손상되었습니다")
+	}
+	plain, err := st.aead.Open(nil, sealed[:n], sealed[n:], []byte(token))
+	if err != nil {
+		return nil, fmt.Errorf("저장된 로그인 정보 복호화 실패: %w", err)
+	}
+
+	var state loginState
+	if err := json.Unmarshal(plain, &state); err != nil {
+		return nil, err
+	}
+	if time.Since(state.SavedAt) > loginStateTTL {
+		return nil, errors.New("저장된 로그인 정보가 만료되었습니다")
+	}
+	return &state, nil
+}
+
+func (st *loginStore) remove(token string) {
+	if path, err := st.path(token); err == nil {
+		_ = os.Remove(path)
+	}
+}
+
+// loginToken 은 요청의 로그인 토큰 쿠키 값을 돌려줍니다.
+func loginToken(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(loginCookieName)
+	if err != nil || !loginTokenFormat.MatchString(cookie.Value) {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+func setLoginCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(loginStateTTL.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func readStorage(page *rod.Page, name string) (map[string]string, error) {
+	res, err := page.Eval(`(name) => {
+		const store = window[name];
+		const out = {};
+		for (let i = 0; i < store.length; i++) {
+			const key = store.key(i);
+			out[key] = store.getItem(key);
+		}
+		return out;
+	}`, name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]string{}
+	if err := res.Value.Unmarshal(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func writeStorage(page *rod.Page, name string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	_, err := page.Eval(`(name, values) => {
+		const store = window[name];
+		for (const [key, value] of Object.entries(values)) {
+			store.setItem(key, value);
+		}
+	}`, name, values)
+	return err
+}
+
+// exportLoginState 는 세션의 쿠키와 사이트 스토리지를 읽어옵니다.
+func exportLoginState(browser *rod.Browser, page *rod.Page) (*loginState, error) {
+	cookies, err := browser.Context(page.GetContext()).GetCookies()
+	if err != nil {
+		return nil, fmt.Errorf("쿠키 읽기 실패: %w", err)
+	}
+
+	local, err := readStorage(page, "localStorage")
+	if err != nil {
+		return nil, fmt.Errorf("localStorage 읽기 실패: %w", err)
+	}
+	session, err := readStorage(page, "sessionStorage")
+	if err != nil {
+		return nil, fmt.Errorf("sessionStorage 읽기 실패: %w", err)
+	}
+
+	return &loginState{
+		Cookies:        cookies,
+		LocalStorage:   local,
+		SessionStorage: session,
+		SavedAt:        time.Now(),
+	}, nil
+}
+
+// restoreLoginState 는 저장된 쿠키와 스토리지를 새 페이지에 넣고 메인 페이지를 다시 엽니다.
+func restoreLoginState(browser *rod.Browser, page *rod.Page, site siteAdapter, state *loginState) error {
+	if err := browser.Context(page.GetContext()).SetCookies(proto.CookiesToParams(state.Cookies)); err != nil {
+		return fmt.Errorf("쿠키 복원 실패: %w", err)
+	}
+
+	// 스토리지는 같은 출처의 문서에서만 쓸 수 있으므로 사이트를 먼저 엽니다.
+	if err := navigate(page, site.MainURL()); err != nil {
+		return err
+	}
+	if err := writeStorage(page, "localStorage", state.LocalStorage); err != nil {
+		return fmt.Errorf("localStorage 복원 실패: %w", err)
+	}
+	if err := writeStorage(page, "sessionStorage", state.SessionStorage); err != nil {
+		return fmt.Errorf("sessionStorage 복원 실패: %w", err)
+	}
+	return reload(page)
+}
+
+// isLoggedIn 은 현재 페이지에 로그아웃 링크(logoutText)가 보이는지로 로그인 여부를 판단합니다.
+func isLoggedIn(page *rod.Page, logoutText string) (bool, error) {
+	res, err := page.Eval(`(text) => Array.from(document.querySelectorAll("a, button"))
+		.some((el) => (el.textContent || "").trim() === text)`, logoutText)
+	if err != nil {
+		return false, err
+	}
+	return res.Value.Bool(), nil
+}
+
+// saveLogin 은 로그인 성공 후 상태를 저장하고 토큰 쿠키를 내려줍니다.
+func (s *userSession) saveLogin(w http.ResponseWriter, r *http.Request, page *rod.Page) {
+	if logins == nil {
+		return
+	}
+
+	token, ok := loginToken(r)
+	if !ok {
+		var err error
+		if token, err = generateSessionID(); err != nil {
+			log.Printf("로그인 토큰 생성 실패: %v", err)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	browser := s.browser
+	s.mu.Unlock()
+	if browser == nil {
+		return
+	}
+
+	state, err := exportLoginState(browser, page)
+	if err != nil {
+		log.Printf("로그인 상태 내보내기 실패: %v", err)
+		s.pushError("로그인 상태를 저장하지 못했습니다.")
+		return
+	}
+	state.Site = s.site.ID()
+	if err := logins.save(token, state); err != nil {
+		log.Printf("로그인 상태 저장 실패: %v", err)
+		s.pushError("로그인 상태를 저장하지 못했습니다.")
+		return
+	}
+
+	setLoginCookie(w, token)
+	s.pushInfo("로그인 상태를 저장했습니다. 다음 실행 때 자동으로 복원합니다.")
+}
+
+// restoreLogin 은 저장된 로그인 상태가 있으면 복원하고, 여전히 로그인되어 있는지 확인합니다.
+func (s *userSession) restoreLogin(r *http.Request, browser *rod.Browser, page *rod.Page) bool {
+	if logins == nil {
+		return false
+	}
+
+	token, ok := loginToken(r)
+	if !ok {
+		return false
+	}
+
+	state, err := logins.load(token)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("저장된 로그인 상태 읽기 실패: %v", err)
+			logins.remove(token)
+		}
+		return false
+	}
+	// 사이트를 기록하기 전에 저장한 상태는 기본 사이트의 것입니다.
+	if state.Site == "" {
+		state.Site = sites[0].ID()
+	}
+	if state.Site != s.site.ID() {
+		return false
+	}
+
+	if err := s.step("저장된 로그인 정보를 복원합니다.", func() error {
+		return restoreLoginState(browser, page, s.site, state)
+	}); err != nil {
+		log.Printf("로그인 상태 복원 실패: %v", err)
+		s.pushError(err.Error())
+		return false
+	}
+
+	loggedIn, err := s.site.LoggedIn(page)
+	if err != nil || !loggedIn {
+		logins.remove(token)
+		s.pushInfo("저장된 로그인이 만료되어 다시 로그인해야 합니다.")
+		return false
+	}
+
+	s.pushInfo("저장된 로그인으로 복원했습니다.")
+	return true
+}
+
+func ForgetLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if logins != nil {
+		if token, ok := loginToken(r); ok {
+			logins.remove(token)
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     loginCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("저장된 로그인 정보 삭제 완료"))
+}
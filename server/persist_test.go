@@ -0,0 +1,182 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	testTokenA = "0123456789abcdef0123456789abcdef"
+	testTokenB = "fedcba9876543210fedcba9876543210"
+)
+
+func newTestLoginStore(t *testing.T, secret string) *loginStore {
+	t.Helper()
+	st, err := newLoginStore(t.TempDir(), secret)
+	if err != nil {
+		t.Fatalf("newLoginStore: %v", err)
+	}
+	return st
+}
+
+func testLoginState(savedAt time.Time) *loginState {
+	return &loginState{
+		Site:           "auc-hogye",
+		Cookies:        []*proto.NetworkCookie{{Name: "JSESSIONID", Value: "abc", Domain: "www.auc.or.kr", Path: "/"}},
+		LocalStorage:   map[string]string{"k": "v"},
+		SessionStorage: map[string]string{},
+		SavedAt:        savedAt.UTC().Truncate(time.Second),
+	}
+}
+
+func TestLoginStoreRoundTrip(t *testing.T) {
+	st := newTestLoginStore(t, "secret")
+	want := testLoginState(time.Now())
+
+	if err := st.save(testTokenA, want); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	raw, err := os.ReadFile(filepath.Join(st.dir, testTokenA+".bin"))
+	if err != nil {
+		t.Fatalf("read sealed file: %v", err)
+	}
+	if strings.Contains(string(raw), "JSESSIONID") {
+		t.Error("sealed file contains the cookie in plain text")
+	}
+
+	got, err := st.load(testTokenA)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("load = %+v, want %+v", got, want)
+	}
+
+	st.remove(testTokenA)
+	if _, err := st.load(testTokenA); !os.IsNotExist(err) {
+		t.Errorf("load after remove: err = %v, want not exist", err)
+	}
+}
+
+// 토큰을 추가 인증 데이터로 쓰므로 다른 토큰 이름으로 옮긴 파일은 열리지 않아야 합니다.
+func TestLoginStoreBindsToken(t *testing.T) {
+	st := newTestLoginStore(t, "secret")
+	if err := st.save(testTokenA, testLoginState(time.Now())); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	sealed, err := os.ReadFile(filepath.Join(st.dir, testTokenA+".bin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(st.dir, testTokenB+".bin"), sealed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := st.load(testTokenB); err == nil {
+		t.Fatal("load succeeded with a blob saved under another token")
+	}
+}
+
+func TestLoginStoreRejectsTampering(t *testing.T) {
+	st := newTestLoginStore(t, "secret")
+	if err := st.save(testTokenA, testLoginState(time.Now())); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	path := filepath.Join(st.dir, testTokenA+".bin")
+	sealed, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string][]byte{
+		"본문 변조": func() []byte {
+			b := append([]byte(nil), sealed...)
+			b[len(b)-1] ^= 0x01
+			return b
+		}(),
+		"nonce 변조": func() []byte {
+			b := append([]byte(nil), sealed...)
+			b[0] ^= 0x01
+			return b
+		}(),
+		"잘림":    sealed[:st.aead.NonceSize()+4],
+		"너무 짧음": sealed[:3],
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := st.load(testTokenA); err == nil {
+				t.Fatal("load succeeded with tampered data")
+			}
+		})
+	}
+
+	other, err := newLoginStore(st.dir, "other secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, sealed, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.load(testTokenA); err == nil {
+		t.Fatal("load succeeded with a different key")
+	}
+}
+
+func TestLoginStoreExpires(t *testing.T) {
+	st := newTestLoginStore(t, "secret")
+
+	if err := st.save(testTokenA, testLoginState(time.Now().Add(-loginStateTTL-time.Hour))); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := st.load(testTokenA); err == nil || !strings.Contains(err.Error(), "만료") {
+		t.Errorf("load expired state: err = %v, want expiry error", err)
+	}
+
+	if err := st.save(testTokenA, testLoginState(time.Now().Add(-loginStateTTL+time.Hour))); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, err := st.load(testTokenA); err != nil {
+		t.Errorf("load state within TTL: %v", err)
+	}
+}
+
+func TestLoginStoreRejectsBadTokens(t *testing.T) {
+	st := newTestLoginStore(t, "secret")
+
+	for _, token := range []string{
+		"",
+		"../../etc/passwd",
+		"../" + testTokenA[3:],
+		testTokenA + "/..",
+		strings.ToUpper(testTokenA),
+		testTokenA[:31],
+		testTokenA + "0",
+		"0123456789abcdef0123456789abcdeg",
+	} {
+		if err := st.save(token, testLoginState(time.Now())); err == nil {
+			t.Errorf("save(%q) succeeded", token)
+		}
+		if _, err := st.load(token); err == nil || os.IsNotExist(err) {
+			t.Errorf("load(%q): err = %v, want token error", token, err)
+		}
+	}
+
+	entries, err := os.ReadDir(st.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("store dir has %d entries, want none", len(entries))
+	}
+}
@@ -0,0 +1,180 @@
+package server
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// 채우기에 실패했을 때 다시 시도하기까지의 대기 시간
+const poolRetryDelay = 30 * time.Second
+
+// pooledPage 는 메인 페이지까지 미리 열어 둔 시크릿 컨텍스트입니다.
+type pooledPage struct {
+	browser   *rod.Browser
+	page      *rod.Page
+	createdAt time.Time
+}
+
+func (pp *pooledPage) close() {
+	if err := pp.browser.Close(); err != nil {
+		log.Printf("대기 페이지 정리 실패: %v", err)
+	}
+}
+
+// pagePool 은 Launch 가 바로 넘겨줄 수 있도록 준비된 페이지를 보관합니다.
+// POOL_SIZE 로 보관 개수를, POOL_MAX_IDLE 로 한 페이지를 보관할 최대 시간을 정합니다.
+type pagePool struct {
+	size    int
+	maxIdle time.Duration
+
+	mu      sync.Mutex
+	idle    []*pooledPage
+	filling int
+	refill  chan struct{}
+}
+
+var pool = newPagePool(intFromEnv("POOL_SIZE", 1), durationFromEnv("POOL_MAX_IDLE", 15*time.Minute))
+
+func newPagePool(size int, maxIdle time.Duration) *pagePool {
+	return &pagePool{
+		size:    max(size, 0),
+		maxIdle: maxIdle,
+		refill:  make(chan struct{}, 1),
+	}
+}
+
+func intFromEnv(key string, fallback int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("%s 값 %q을(를) 해석하지 못해 기본값 %d을(를) 사용합니다.", key, value, fallback)
+		return fallback
+	}
+	return n
+}
+
+func (p *pagePool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// take 는 준비된 페이지를 하나 꺼냅니다. 꺼낸 뒤에는 백그라운드에서 다시 채웁니다.
+func (p *pagePool) take() (*pooledPage, bool) {
+	if p.size == 0 {
+		return nil, false
+	}
+	defer p.requestRefill()
+
+	for {
+		p.mu.Lock()
+		if len(p.idle) == 0 {
+			p.mu.Unlock()
+			return nil, false
+		}
+		pp := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+		p.mu.Unlock()
+
+		// 공유 브라우저가 재시작되었다면 보관 중인 페이지는 이미 죽은 상태입니다.
+		if time.Since(pp.createdAt) < p.maxIdle {
+			timed := pp.page.Timeout(2 * time.Second)
+			_, err := timed.Info()
+			timed.CancelTimeout()
+			if err == nil {
+				return pp, true
+			}
+		}
+		pp.close()
+	}
+}
+
+// reap 은 POOL_MAX_IDLE 을 넘긴 대기 페이지를 정리합니다. 세션 정리 주기에 함께 호출됩니다.
+func (p *pagePool) reap(now time.Time) {
+	p.mu.Lock()
+	var expired []*pooledPage
+	kept := p.idle[:0]
+	for _, pp := range p.idle {
+		if now.Sub(pp.createdAt) >= p.maxIdle {
+			expired = append(expired, pp)
+			continue
+		}
+		kept = append(kept, pp)
+	}
+	p.idle = kept
+	p.mu.Unlock()
+
+	for _, pp := range expired {
+		pp.close()
+	}
+	if len(expired) > 0 {
+		log.Printf("오래된 대기 페이지 %d개를 정리했습니다.", len(expired))
+		p.requestRefill()
+	}
+}
+
+func (p *pagePool) run() {
+	if p.size == 0 {
+		return
+	}
+
+	p.requestRefill()
+	for range p.refill {
+		for p.needsMore() {
+			pp, err := p.create()
+
+			p.mu.Lock()
+			p.filling--
+			if err == nil {
+				p.idle = append(p.idle, pp)
+			}
+			p.mu.Unlock()
+
+			if err != nil {
+				log.Printf("대기 페이지 준비 실패: %v", err)
+				time.Sleep(poolRetryDelay)
+			}
+		}
+	}
+}
+
+// needsMore 는 더 채워야 하면 채우는 중인 개수를 올리고 true 를 반환합니다.
+func (p *pagePool) needsMore() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.idle)+p.filling >= p.size {
+		return false
+	}
+	p.filling++
+	return true
+}
+
+func (p *pagePool) create() (*pooledPage, error) {
+	browser, page, err := newIncognitoPage()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := navigate(page, defaultSite.MainURL()); err != nil {
+		_ = browser.Close()
+		return nil, err
+	}
+
+	return &pooledPage{
+		browser:   browser,
+		page:      page,
+		createdAt: time.Now(),
+	}, nil
+}
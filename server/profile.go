@@ -0,0 +1,147 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 기본 대상 프로필. PROFILES_FILE 환경 변수로 외부 파일을 지정하면 그 파일을 우선 사용합니다.
+//
+//go:embed config/profiles.json
+var defaultProfilesJSON []byte
+
+// targetProfile 은 강습 신청 대상(강습 구분, 강습 과정, 요일, 시간)을 정의합니다.
+// Site 가 있으면 그 사이트 어댑터로 실행한 세션에서만 쓸 수 있습니다.
+type targetProfile struct {
+	Name         string `json:"name"`
+	Label        string `json:"label"`
+	Site         string `json:"site,omitempty"`
+	Area         string `json:"area"`
+	EntranceType string `json:"entranceType"`
+	DayPattern   string `json:"dayPattern"`
+	TimeRange    string `json:"timeRange"`
+}
+
+type profileConfig struct {
+	Profiles []targetProfile `json:"profiles"`
+}
+
+var (
+	profileMu sync.RWMutex
+	profiles  []targetProfile
+)
+
+func loadProfiles() error {
+	data := defaultProfilesJSON
+	source := "기본 설정"
+
+	if path := strings.TrimSpace(os.Getenv("PROFILES_FILE")); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("프로필 파일 읽기 실패 (%s): %w", path, err)
+		}
+		data = b
+		source = path
+	}
+
+	loaded, err := parseProfiles(data)
+	if err != nil {
+		return fmt.Errorf("프로필 파싱 실패 (%s): %w", source, err)
+	}
+
+	profileMu.Lock()
+	profiles = loaded
+	profileMu.Unlock()
+
+	log.Printf("대상 프로필 %d개를 불러왔습니다. (%s)", len(loaded), source)
+	return nil
+}
+
+func parseProfiles(data []byte) ([]targetProfile, error) {
+	var cfg profileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	for i := range cfg.Profiles {
+		p := &cfg.Profiles[i]
+		p.Name = strings.TrimSpace(p.Name)
+		if p.Name == "" {
+			return nil, fmt.Errorf("%d번째 프로필에 name이 없습니다", i+1)
+		}
+		if _, exists := seen[p.Name]; exists {
+			return nil, fmt.Errorf("프로필 이름 %q이(가) 중복되었습니다", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+
+		if p.Label == "" {
+			p.Label = p.Name
+		}
+		if p.Site != "" {
+			if _, ok := findSite(p.Site); !ok {
+				return nil, fmt.Errorf("프로필 %q의 site %q은(는) 알 수 없는 사이트입니다", p.Name, p.Site)
+			}
+		}
+		if p.Area == "" || p.EntranceType == "" || p.DayPattern == "" || p.TimeRange == "" {
+			return nil, fmt.Errorf("프로필 %q에 area, entranceType, dayPattern, timeRange가 모두 필요합니다", p.Name)
+		}
+	}
+
+	return cfg.Profiles, nil
+}
+
+func listProfiles() []targetProfile {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+
+	out := make([]targetProfile, len(profiles))
+	copy(out, profiles)
+	return out
+}
+
+func findProfile(name string) (targetProfile, bool) {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return targetProfile{}, false
+}
+
+// findSiteProfile 은 findProfile 과 같지만 site 에서 쓸 수 없는 프로필은 찾지 못한 것으로 봅니다.
+func findSiteProfile(name string, site siteAdapter) (targetProfile, bool) {
+	p, ok := findProfile(name)
+	if !ok || (p.Site != "" && p.Site != site.ID()) {
+		return targetProfile{}, false
+	}
+	return p, true
+}
+
+// Profiles 는 대상 프로필 목록을 돌려줍니다. ?site= 를 주면 그 사이트에서 쓸 수 있는 프로필만 돌려줍니다.
+func Profiles(w http.ResponseWriter, r *http.Request) {
+	list := listProfiles()
+	if site := strings.TrimSpace(r.URL.Query().Get("site")); site != "" {
+		filtered := list[:0]
+		for _, p := range list {
+			if p.Site == "" || p.Site == site {
+				filtered = append(filtered, p)
+			}
+		}
+		list = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		log.Printf("프로필 응답 인코딩 실패: %v", err)
+	}
+}
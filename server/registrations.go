@@ -0,0 +1,333 @@
+package server
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// registration 은 신청내역 표의 한 줄입니다.
+//
+// ID 는 줄 내용으로 만든 값이라 내용이 바뀌면 달라집니다. 취소할 때 목록을 본 뒤 줄이 바뀌지 않았는지 확인하는 데 씁니다.
+type registration struct {
+	ID              string            `json:"id"`
+	Lesson          string            `json:"lesson"`
+	Period          string            `json:"period"`
+	Status          string            `json:"status"`
+	PaymentDeadline string            `json:"paymentDeadline"`
+	Cancelable      bool              `json:"cancelable"`
+	Columns         map[string]string `json:"columns"`
+
+	row int
+}
+
+// registrationRow 는 페이지에서 읽어온 표 한 줄의 원본입니다.
+type registrationRow struct {
+	Headers    []string `json:"headers"`
+	Cells      []string `json:"cells"`
+	Cancelable bool     `json:"cancelable"`
+}
+
+// 열 제목으로 항목을 찾습니다. 앞에 있는 키워드가 우선입니다.
+var registrationColumns = []struct {
+	field    string
+	keywords []string
+}{
+	{"paymentDeadline", []string{"결제기한", "납부기한", "결제마감", "입금기한"}},
+	{"period", []string{"수강기간", "강습기간", "교육기간", "기간"}},
+	{"status", []string{"신청상태", "진행상태", "결제상태", "상태"}},
+	{"lesson", []string{"강습명", "강좌명", "프로그램", "강습", "강좌"}},
+}
+
+// registrationRowsScript 는 본문 표의 tbody 줄을 열 제목과 함께 돌려줍니다.
+// 취소 버튼은 줄 안에서 글자에 cancelText(기본 "취소")가 들어간 a/button 입니다.
+const registrationRowsScript = `(cancelText) => {
+	const out = [];
+	document.querySelectorAll("table").forEach((table) => {
+		const headers = Array.from(table.querySelectorAll("thead th, thead td")).map((th) => (th.innerText || "").trim());
+		if (headers.length === 0) return;
+		table.querySelectorAll("tbody tr").forEach((tr) => {
+			const cells = Array.from(tr.querySelectorAll("td")).map((td) => (td.innerText || "").trim());
+			if (cells.length < 2) return;
+			const cancelable = Array.from(tr.querySelectorAll("a, button")).some((el) => (el.textContent || "").trim().includes(cancelText));
+			out.push({ headers, cells, cancelable });
+		});
+	});
+	return out;
+}`
+
+func parseRegistration(row registrationRow, index int) registration {
+	reg := registration{
+		Cancelable: row.Cancelable,
+		Columns:    map[string]string{},
+		row:        index,
+	}
+
+	fields := map[string]*string{
+		"lesson":          &reg.Lesson,
+		"period":          &reg.Period,
+		"status":          &reg.Status,
+		"paymentDeadline": &reg.PaymentDeadline,
+	}
+
+	for i, cell := range row.Cells {
+		header := fmt.Sprintf("열%d", i+1)
+		if i < len(row.Headers) && row.Headers[i] != "" {
+			header = row.Headers[i]
+		}
+		reg.Columns[header] = cell
+
+		// 열 제목은 먼저 맞는 항목 하나에만 씁니다. 이미 채워진 항목이면 뒤 항목으로 넘기지 않습니다.
+		compact := strings.ReplaceAll(header, " ", "")
+		for _, col := range registrationColumns {
+			if !containsAny(compact, col.keywords) {
+				continue
+			}
+			if target := fields[col.field]; *target == "" {
+				*target = cell
+			}
+			break
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.Join(row.Cells, "\x1f")))
+	reg.ID = hex.EncodeToString(sum[:])[:12]
+	return reg
+}
+
+func containsAny(s string, keywords []string) bool {
+	for _, kw := range keywords {
+		if strings.Contains(s, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func readRegistrations(page *rod.Page, cancelText string) ([]registration, error) {
+	res, err := page.Eval(registrationRowsScript, cancelText)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []registrationRow
+	if err := res.Value.Unmarshal(&rows); err != nil {
+		return nil, err
+	}
+
+	regs := make([]registration, 0, len(rows))
+	for i, row := range rows {
+		reg := parseRegistration(row, i)
+		// 신청내역이 없을 때 나오는 안내 줄은 건너뜁니다.
+		if reg.Lesson == "" && reg.Status == "" {
+			continue
+		}
+		regs = append(regs, reg)
+	}
+	return regs, nil
+}
+
+// openRegistrations 는 신청내역 페이지로 이동합니다.
+func (s *userSession) openRegistrations(page *rod.Page) error {
+	return s.step("신청내역 페이지로 이동합니다.", func() error {
+		if url := s.site.RegistrationsURL(); url != "" {
+			return navigate(page, url)
+		}
+
+		if err := navigate(page, s.site.MainURL()); err != nil {
+			return err
+		}
+		res, err := page.Eval(`(text) => {
+			const link = Array.from(document.querySelectorAll("a[href]"))
+				.find((a) => (a.textContent || "").replace(/\s+/g, "").includes(text));
+			return link ? link.href : "";
+		}`, siteText(s.site.ID(), "registrationsLink"))
+		if err != nil {
+			return err
+		}
+		href := res.Value.Str()
+		if href == "" || strings.HasPrefix(href, "javascript:") {
+			return errNotFound("신청내역 링크를 찾지 못했습니다. 사이트 설정에 신청내역 경로를 지정해주세요")
+		}
+		return navigate(page, href)
+	})
+}
+
+// loadRegistrations 는 신청내역 페이지를 열고 표를 읽습니다. 로그인이 풀렸으면 알려줍니다.
+func (s *userSession) loadRegistrations(page *rod.Page) ([]registration, error) {
+	if err := s.openRegistrations(page); err != nil {
+		return nil, err
+	}
+	s.handleWaitPage(page)
+
+	if s.site.IsLoginPage(currentURL(page)) {
+		return nil, &stepError{Step: "신청내역 확인", Err: fmt.Errorf("로그인이 필요합니다. 먼저 로그인해주세요")}
+	}
+
+	var regs []registration
+	err := s.step("신청내역을 읽는 중입니다.", func() error {
+		var err error
+		regs, err = readRegistrations(page, siteText(s.site.ID(), "cancel"))
+		return err
+	})
+	return regs, err
+}
+
+func Registrations(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	regs, err := session.loadRegistrations(page)
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	session.pushInfo(fmt.Sprintf("신청내역 %d건을 읽었습니다.", len(regs)))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(regs); err != nil {
+		log.Printf("신청내역 응답 인코딩 실패: %v", err)
+	}
+}
+
+// CancelRegistration 은 신청내역 한 건을 취소합니다.
+// 실수로 취소하지 않도록 본문의 confirm 이 해당 줄의 강습명과 정확히 같아야 합니다.
+func CancelRegistration(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	defer r.Body.Close()
+	var payload struct {
+		ID      string `json:"id"`
+		Confirm string `json:"confirm"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "요청 본문 파싱에 실패했습니다.", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(payload.ID) == "" || strings.TrimSpace(payload.Confirm) == "" {
+		http.Error(w, "취소할 신청내역 ID와 확인용 강습명이 필요합니다.", http.StatusBadRequest)
+		return
+	}
+
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	regs, err := session.loadRegistrations(page)
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	var target *registration
+	for i := range regs {
+		if regs[i].ID == payload.ID {
+			target = &regs[i]
+			break
+		}
+	}
+	if target == nil {
+		http.Error(w, "신청내역이 바뀌었거나 찾을 수 없습니다. 목록을 다시 불러와주세요.", http.StatusNotFound)
+		return
+	}
+	if strings.TrimSpace(payload.Confirm) != target.Lesson {
+		http.Error(w, "확인용 강습명이 일치하지 않습니다.", http.StatusBadRequest)
+		return
+	}
+	if !target.Cancelable {
+		http.Error(w, "취소할 수 없는 신청내역입니다.", http.StatusConflict)
+		return
+	}
+
+	// 확인 대화상자는 대화상자 규칙(기본 accept)이 처리합니다.
+	since := time.Now()
+	if err := session.step(fmt.Sprintf("%s 신청을 취소합니다.", target.Lesson), func() error {
+		// registrationRowsScript 와 같은 기준으로 줄을 세어 같은 줄을 찾습니다.
+		res, err := page.Eval(`(index, cancelText) => {
+			const rows = [];
+			document.querySelectorAll("table").forEach((table) => {
+				if (table.querySelectorAll("thead th, thead td").length === 0) return;
+				table.querySelectorAll("tbody tr").forEach((tr) => {
+					if (tr.querySelectorAll("td").length >= 2) rows.push(tr);
+				});
+			});
+			const tr = rows[index];
+			if (!tr) return false;
+			const btn = Array.from(tr.querySelectorAll("a, button")).find((el) => (el.textContent || "").trim().includes(cancelText));
+			if (!btn) return false;
+			btn.click();
+			return true;
+		}`, target.row, siteText(session.site.ID(), "cancel"))
+		if err != nil {
+			return err
+		}
+		if !res.Value.Bool() {
+			return errNotFound("취소 버튼을 찾지 못했습니다")
+		}
+		if err := pause(page, 2*time.Second); err != nil {
+			return err
+		}
+		return waitLoad(page)
+	}); err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	dialogs := session.dialogsSince(since)
+
+	// 목록을 다시 읽어 상태가 바뀌었는지 확인합니다.
+	after, err := session.loadRegistrations(page)
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	resp := struct {
+		Message string        `json:"message"`
+		Dialogs []string      `json:"dialogs"`
+		Entry   *registration `json:"entry,omitempty"`
+	}{
+		Message: "신청내역에서 사라졌습니다. 취소가 완료되었습니다.",
+		Dialogs: dialogs,
+	}
+	for i := range after {
+		if after[i].Lesson == target.Lesson && after[i].Period == target.Period {
+			resp.Entry = &after[i]
+			resp.Message = fmt.Sprintf("취소를 요청했습니다. 현재 상태: %s", after[i].Status)
+			break
+		}
+	}
+
+	session.pushInfo(fmt.Sprintf("[신청내역] %s: %s", target.Lesson, resp.Message))
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("신청 취소 응답 인코딩 실패: %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package server
+
+import "testing"
+
+func TestParseRegistration(t *testing.T) {
+	tests := []struct {
+		name     string
+		row      registrationRow
+		want     registration
+		wantCols map[string]string
+	}{
+		{
+			name: "기본 열",
+			row: registrationRow{
+				Headers: []string{"강습명", "수강기간", "결제기한", "신청상태"},
+				Cells:   []string{"새벽 수영 A", "2026-11-01 ~ 2026-11-30", "2026-10-20", "결제대기"},
+			},
+			want: registration{Lesson: "새벽 수영 A", Period: "2026-11-01 ~ 2026-11-30", PaymentDeadline: "2026-10-20", Status: "결제대기"},
+		},
+		{
+			// "결제기한" 을 기간보다 먼저 확인하므로 결제기한 열이 앞에 있어도 기간이 되지 않습니다.
+			name: "결제기한이 기간보다 우선",
+			row: registrationRow{
+				Headers: []string{"결제 기한", "기간", "강좌명"},
+				Cells:   []string{"2026-10-20 18:00", "11월", "스쿼시 초급"},
+			},
+			want: registration{Lesson: "스쿼시 초급", Period: "11월", PaymentDeadline: "2026-10-20 18:00"},
+		},
+		{
+			name: "신청상태가 있으면 결제상태는 열로만",
+			row: registrationRow{
+				Headers: []string{"프로그램", "신청 상태", "결제상태"},
+				Cells:   []string{"스쿼시 중급", "신청완료", "미결제"},
+			},
+			want:     registration{Lesson: "스쿼시 중급", Status: "신청완료"},
+			wantCols: map[string]string{"결제상태": "미결제"},
+		},
+		{
+			// 두 번째 기간 열은 "강습" 이 들어 있어도 강습명이 되지 않습니다.
+			name: "이미 채운 항목은 뒤 항목으로 넘기지 않음",
+			row: registrationRow{
+				Headers: []string{"수강기간", "강습기간", "강습명"},
+				Cells:   []string{"11월", "2026-11-01 ~ 2026-11-30", "스쿼시 초급"},
+			},
+			want: registration{Lesson: "스쿼시 초급", Period: "11월"},
+		},
+		{
+			name: "열 제목이 없으면 열N",
+			row: registrationRow{
+				Headers: []string{"강습명", ""},
+				Cells:   []string{"스쿼시 초급", "접수", "비고"},
+			},
+			want:     registration{Lesson: "스쿼시 초급"},
+			wantCols: map[string]string{"열2": "접수", "열3": "비고"},
+		},
+		{
+			name: "취소 가능 여부",
+			row: registrationRow{
+				Headers:    []string{"강습명", "상태"},
+				Cells:      []string{"스쿼시 초급", "접수"},
+				Cancelable: true,
+			},
+			want: registration{Lesson: "스쿼시 초급", Status: "접수", Cancelable: true},
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseRegistration(tt.row, i)
+			if got.Lesson != tt.want.Lesson || got.Period != tt.want.Period || got.Status != tt.want.Status ||
+				got.PaymentDeadline != tt.want.PaymentDeadline || got.Cancelable != tt.want.Cancelable {
+				t.Errorf("parseRegistration = %+v, want %+v", got, tt.want)
+			}
+			if got.row != i {
+				t.Errorf("row = %d, want %d", got.row, i)
+			}
+			if len(got.Columns) != len(tt.row.Cells) {
+				t.Errorf("Columns = %v, want %d entries", got.Columns, len(tt.row.Cells))
+			}
+			for k, v := range tt.wantCols {
+				if got.Columns[k] != v {
+					t.Errorf("Columns[%q] = %q, want %q", k, got.Columns[k], v)
+				}
+			}
+			if len(got.ID) != 12 {
+				t.Errorf("ID = %q, want 12 hex chars", got.ID)
+			}
+		})
+	}
+}
+
+func TestParseRegistrationID(t *testing.T) {
+	row := registrationRow{Headers: []string{"강습명", "상태"}, Cells: []string{"스쿼시 초급", "접수"}}
+	a := parseRegistration(row, 0)
+	b := parseRegistration(row, 3)
+	if a.ID != b.ID {
+		t.Errorf("ID depends on row index: %q != %q", a.ID, b.ID)
+	}
+
+	// 셀 경계가 다르면 내용을 이어 붙인 값이 같아도 다른 ID 입니다.
+	c := parseRegistration(registrationRow{Cells: []string{"스쿼시 초", "급접수"}}, 0)
+	if c.ID == a.ID {
+		t.Errorf("ID collides across cell boundaries: %q", c.ID)
+	}
+}
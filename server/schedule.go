@@ -0,0 +1,387 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 오픈 시각 기준 사전 작업 시점
+	schedulePrepareLead  = 60 * time.Second
+	scheduleReselectLead = 5 * time.Second
+	// 오픈 이후 신청 버튼을 재시도하는 기본 시간과 간격
+	scheduleDefaultWindow = 3 * time.Minute
+	scheduleRetryInterval = 700 * time.Millisecond
+)
+
+var kst = time.FixedZone("KST", 9*60*60)
+
+type scheduleStatus struct {
+	Profile  string    `json:"profile"`
+	OpenAt   time.Time `json:"openAt"`
+	Deadline time.Time `json:"deadline"`
+	State    string    `json:"state"`
+	Attempts int       `json:"attempts"`
+
+	Outcome *applyOutcome `json:"outcome,omitempty"`
+}
+
+// applySchedule 은 세션별 예약 신청 한 건의 상태를 보관합니다.
+type applySchedule struct {
+	mu     sync.Mutex
+	status scheduleStatus
+	cancel context.CancelFunc
+}
+
+func (s *applySchedule) setState(state string) {
+	s.mu.Lock()
+	s.status.State = state
+	s.mu.Unlock()
+}
+
+func (s *applySchedule) setOutcome(out applyOutcome) {
+	s.mu.Lock()
+	s.status.Outcome = &out
+	s.mu.Unlock()
+}
+
+func (s *applySchedule) nextAttempt() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Attempts++
+	return s.status.Attempts
+}
+
+func (s *applySchedule) snapshot() scheduleStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+// parseOpenTime 은 "2006-01-02 15:04:05" 형식(끝의 " KST"는 생략 가능)을 KST 기준으로 해석합니다.
+func parseOpenTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "KST"))
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04"} {
+		if t, err := time.ParseInLocation(layout, value, kst); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("오픈 시각 형식이 올바르지 않습니다: %q", value)
+}
+
+func (s *userSession) cancelSchedule() {
+	if s == nil {
+		return
+	}
+
+	s.scheduleMu.Lock()
+	sched := s.schedule
+	s.schedule = nil
+	s.scheduleMu.Unlock()
+
+	if sched != nil && sched.cancel != nil {
+		sched.cancel()
+	}
+}
+
+// hasSchedule 은 진행 중인 예약 신청이 있는지 확인합니다.
+func (s *userSession) hasSchedule() bool {
+	if s == nil {
+		return false
+	}
+
+	s.scheduleMu.Lock()
+	sched := s.schedule
+	s.scheduleMu.Unlock()
+
+	if sched == nil {
+		return false
+	}
+
+	switch sched.snapshot().State {
+	case "waiting", "preparing", "applying":
+		return true
+	}
+	return false
+}
+
+// scheduleBusy 는 예약 신청이 곧 브라우저를 써야 하는지 확인합니다.
+// 사전 이동이나 신청 중이면, 또는 사전 이동 시각이 margin 안으로 다가왔으면 true 입니다.
+func (s *userSession) scheduleBusy(margin time.Duration) bool {
+	if s == nil {
+		return false
+	}
+
+	s.scheduleMu.Lock()
+	sched := s.schedule
+	s.scheduleMu.Unlock()
+
+	if sched == nil {
+		return false
+	}
+
+	st := sched.snapshot()
+	switch st.State {
+	case "preparing", "applying":
+		return true
+	case "waiting":
+		return clockFor(s.site).Until(st.OpenAt.Add(-schedulePrepareLead)) < margin
+	}
+	return false
+}
+
+func Schedule(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		session.scheduleMu.Lock()
+		sched := session.schedule
+		session.scheduleMu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		var resp any
+		if sched != nil {
+			snap := sched.snapshot()
+			resp = &snap
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("예약 상태 응답 인코딩 실패: %v", err)
+		}
+	case http.MethodPost:
+		defer r.Body.Close()
+		var payload struct {
+			Profile       string `json:"profile"`
+			OpenAt        string `json:"openAt"`
+			WindowSeconds int    `json:"windowSeconds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "요청 본문 파싱에 실패했습니다.", http.StatusBadRequest)
+			return
+		}
+
+		profile, ok := findSiteProfile(payload.Profile, session.site)
+		if !ok {
+			http.Error(w, "알 수 없는 대상 프로필입니다.", http.StatusBadRequest)
+			return
+		}
+
+		openAt, err := parseOpenTime(payload.OpenAt)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		window := scheduleDefaultWindow
+		if payload.WindowSeconds > 0 {
+			window = time.Duration(payload.WindowSeconds) * time.Second
+		}
+
+		if !openAt.Add(window).After(clockFor(session.site).Now()) {
+			http.Error(w, "이미 지난 시각입니다.", http.StatusBadRequest)
+			return
+		}
+
+		session.cancelSchedule()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		sched := &applySchedule{
+			status: scheduleStatus{
+				Profile:  profile.Name,
+				OpenAt:   openAt,
+				Deadline: openAt.Add(window),
+				State:    "waiting",
+			},
+			cancel: cancel,
+		}
+
+		session.scheduleMu.Lock()
+		session.schedule = sched
+		session.scheduleMu.Unlock()
+
+		session.pushInfo(fmt.Sprintf("[%s] %s 예약 신청을 등록했습니다.", profile.Label, openAt.Format("2006-01-02 15:04:05 KST")))
+		go session.runSchedule(ctx, sched, profile)
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("예약 신청 등록 완료"))
+	case http.MethodDelete:
+		session.cancelSchedule()
+		session.pushInfo("예약 신청을 취소했습니다.")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("예약 신청 취소 완료"))
+	default:
+		http.Error(w, "GET, POST, DELETE 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *userSession) runSchedule(ctx context.Context, sched *applySchedule, profile targetProfile) {
+	openAt := sched.status.OpenAt
+	deadline := sched.status.Deadline
+	clock := clockFor(s.site)
+
+	// 1) 사전 이동
+	if !clock.sleepUntil(ctx, openAt.Add(-schedulePrepareLead)) {
+		sched.setState("canceled")
+		return
+	}
+	sched.setState("preparing")
+	s.pushInfo("[예약] 강습 목록 페이지로 미리 이동합니다.")
+	if err := s.schedulePrepare(ctx, profile); err != nil {
+		if ctx.Err() != nil {
+			sched.setState("canceled")
+			return
+		}
+		sched.setState("failed")
+		s.pushError("[예약] 사전 이동에 실패했습니다: " + err.Error())
+		return
+	}
+
+	// 2) 오픈 직전 재선택
+	if !clock.sleepUntil(ctx, openAt.Add(-scheduleReselectLead)) {
+		sched.setState("canceled")
+		return
+	}
+	s.pushInfo("[예약] 오픈 직전 강습 구분/과정을 다시 선택합니다.")
+	if err := s.scheduleReselect(ctx, profile); err != nil {
+		if ctx.Err() != nil {
+			sched.setState("canceled")
+			return
+		}
+		sched.setState("failed")
+		s.pushError("[예약] 강습 구분/과정 재선택에 실패했습니다: " + err.Error())
+		return
+	}
+
+	// 3) 오픈 이후 신청 버튼 재시도
+	if !clock.sleepUntil(ctx, openAt) {
+		sched.setState("canceled")
+		return
+	}
+	sched.setState("applying")
+	s.pushInfo("[예약] 신청 버튼을 찾기 시작합니다.")
+
+	for {
+		if ctx.Err() != nil {
+			sched.setState("canceled")
+			return
+		}
+		if !clock.Now().Before(deadline) {
+			sched.setState("expired")
+			s.pushError("[예약] 마감 시각까지 신청 버튼을 찾지 못했습니다.")
+			return
+		}
+
+		attempt := sched.nextAttempt()
+
+		clicked, out, err := s.scheduleTryApply(ctx, profile)
+		if clicked {
+			if err != nil {
+				log.Printf("예약 신청 클릭 후 로딩 대기 실패: %v", err)
+			}
+			sched.setOutcome(out)
+
+			switch out.Result {
+			case outcomeNotOpen:
+				// 버튼은 보였지만 사이트가 아직 받지 않으면 마감 시각까지 계속 시도합니다.
+				s.pushInfo(fmt.Sprintf("[예약] %d번째 시도: 아직 신청 기간이 아니라고 합니다. 재시도합니다.", attempt))
+				if !clock.sleepUntil(ctx, clock.Now().Add(scheduleRetryInterval)) {
+					sched.setState("canceled")
+					return
+				}
+				continue
+			case outcomeFull, outcomeSessionExpired:
+				sched.setState("failed")
+				s.pushError(fmt.Sprintf("[예약] %d번째 시도에서 신청하지 못했습니다: %s", attempt, out.Message))
+				return
+			case outcomeUnknown:
+				// 버튼은 이미 눌렀으므로 다시 누르면 중복 신청이 될 수 있습니다. 멈추고 사용자가 확인하게 합니다.
+				sched.setState("unknown")
+				s.pushStatus("notice", fmt.Sprintf("[예약] %d번째 시도에서 신청 버튼을 눌렀지만 결과를 확인하지 못했습니다. 신청내역에서 신청 여부를 확인해주세요.", attempt))
+				return
+			default:
+				sched.setState("done")
+				s.pushStatus("notice", fmt.Sprintf("[예약] %d번째 시도: %s", attempt, out.Message))
+				return
+			}
+		}
+
+		if err != nil {
+			log.Printf("예약 신청 %d번째 시도 실패: %v", attempt, err)
+		}
+		if attempt%10 == 1 {
+			if err != nil {
+				s.pushError(fmt.Sprintf("[예약] %d번째 시도 중 오류: %v", attempt, err))
+			} else {
+				s.pushInfo(fmt.Sprintf("[예약] 신청 버튼이 아직 없습니다. 재시도 중... (%d회)", attempt))
+			}
+		}
+
+		if !clock.sleepUntil(ctx, clock.Now().Add(scheduleRetryInterval)) {
+			sched.setState("canceled")
+			return
+		}
+	}
+}
+
+func (s *userSession) schedulePrepare(ctx context.Context, profile targetProfile) error {
+	page, done, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := navigate(page, s.site.LessonListURL()); err != nil {
+		return err
+	}
+	s.handleWaitPage(page)
+	s.pushInfo("[예약] 강습 목록 페이지 진입을 완료했습니다.")
+	return nil
+}
+
+func (s *userSession) scheduleReselect(ctx context.Context, profile targetProfile) error {
+	page, done, err := s.begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if err := s.selectArea(page, profile); err != nil {
+		return err
+	}
+	if err := s.selectEntrance(page, profile); err != nil {
+		return err
+	}
+
+	s.pushInfo("[예약] 강습 구분/과정 재선택을 완료했습니다.")
+	return nil
+}
+
+func (s *userSession) scheduleTryApply(ctx context.Context, profile targetProfile) (bool, applyOutcome, error) {
+	page, done, err := s.begin(ctx)
+	if err != nil {
+		return false, applyOutcome{}, err
+	}
+	defer done()
+
+	base := captureBaseline(page)
+	clicked, err := s.site.ClickLesson(page, profile)
+	if err != nil {
+		return false, applyOutcome{}, err
+	}
+	if clicked {
+		err := waitLoad(page)
+		return true, s.detectOutcome(page, base), err
+	}
+
+	// 목록을 갱신하기 위해 강습 과정을 다시 선택합니다.
+	return false, applyOutcome{}, s.chooseEntrance(page, profile)
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	screencastDefaultFPS     = 5
+	screencastMaxFPS         = 30
+	screencastDefaultQuality = 60
+	screencastBoundary       = "frame"
+)
+
+// castViewer 는 실시간 화면을 보고 있는 클라이언트 하나입니다.
+// 화면 변화가 많아도 interval 보다 자주 보내지 않고, 밀린 프레임은 최신 것으로 바꿉니다.
+type castViewer struct {
+	frames   chan []byte
+	interval time.Duration
+	lastSent time.Time
+}
+
+// screencast 는 세션 페이지의 CDP 화면 전송을 시청자들에게 나눠 줍니다.
+// 첫 시청자가 연결되면 시작하고, 마지막 시청자가 나가면 바로 멈춥니다.
+type screencast struct {
+	page *rod.Page
+
+	mu        sync.Mutex
+	viewers   map[*castViewer]struct{}
+	quality   int
+	lastFrame []byte
+	stop      context.CancelFunc
+}
+
+func (s *userSession) screencast() (*screencast, error) {
+	s.mu.Lock()
+	page := s.page
+	s.mu.Unlock()
+	if page == nil {
+		return nil, errNoPage
+	}
+
+	s.castMu.Lock()
+	defer s.castMu.Unlock()
+
+	if s.cast == nil {
+		s.cast = &screencast{
+			page:    page,
+			viewers: make(map[*castViewer]struct{}),
+		}
+	}
+	return s.cast, nil
+}
+
+// stopScreencast 는 세션 종료 시 화면 전송을 멈추고 모든 시청자 연결을 닫습니다.
+func (s *userSession) stopScreencast() {
+	if s == nil {
+		return
+	}
+
+	s.castMu.Lock()
+	c := s.cast
+	s.cast = nil
+	s.castMu.Unlock()
+
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for v := range c.viewers {
+		close(v.frames)
+	}
+	c.viewers = nil
+	c.stopLocked()
+}
+
+// join 은 시청자를 등록하고 필요하면 화면 전송을 시작합니다.
+// 다른 화질을 요청하면 새 화질로 다시 시작합니다.
+func (c *screencast) join(fps, quality int) (*castViewer, error) {
+	v := &castViewer{
+		frames:   make(chan []byte, 1),
+		interval: time.Second / time.Duration(fps),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.viewers == nil {
+		return nil, errNoPage
+	}
+
+	if c.stop != nil && c.quality != quality {
+		c.stopLocked()
+	}
+	if c.stop == nil {
+		if err := c.startLocked(quality); err != nil {
+			return nil, err
+		}
+	}
+
+	c.viewers[v] = struct{}{}
+	if c.lastFrame != nil {
+		v.frames <- c.lastFrame
+		v.lastSent = time.Now()
+	}
+	return v, nil
+}
+
+// leave 는 시청자를 빼고, 남은 시청자가 없으면 화면 전송을 멈춥니다.
+func (c *screencast) leave(v *castViewer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.viewers[v]; !ok {
+		return
+	}
+	delete(c.viewers, v)
+	close(v.frames)
+
+	if len(c.viewers) == 0 {
+		c.stopLocked()
+	}
+}
+
+func (c *screencast) startLocked(quality int) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	page := c.page.Context(ctx)
+
+	wait := page.EachEvent(func(e *proto.PageScreencastFrame) {
+		// 확인 응답을 보내야 다음 프레임이 옵니다.
+		_ = proto.PageScreencastFrameAck{SessionID: e.SessionID}.Call(page)
+		c.broadcast(e.Data)
+	})
+
+	err := proto.PageStartScreencast{
+		Format:  proto.PageStartScreencastFormatJpeg,
+		Quality: &quality,
+	}.Call(page)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("화면 전송 시작 실패: %w", err)
+	}
+
+	go wait()
+	c.quality = quality
+	c.stop = cancel
+	return nil
+}
+
+func (c *screencast) stopLocked() {
+	if c.stop == nil {
+		return
+	}
+
+	if err := (proto.PageStopScreencast{}).Call(c.page); err != nil {
+		log.Printf("화면 전송 중지 실패: %v", err)
+	}
+	c.stop()
+	c.stop = nil
+	c.lastFrame = nil
+}
+
+func (c *screencast) broadcast(frame []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.lastFrame = frame
+	now := time.Now()
+	for v := range c.viewers {
+		if now.Sub(v.lastSent) < v.interval {
+			continue
+		}
+
+		select {
+		case <-v.frames:
+		default:
+		}
+		v.frames <- frame
+		v.lastSent = now
+	}
+}
+
+func queryInt(r *http.Request, key string, fallback, lo, hi int) int {
+	n, err := strconv.Atoi(r.URL.Query().Get(key))
+	if err != nil {
+		return fallback
+	}
+	return min(max(n, lo), hi)
+}
+
+// Screencast 는 세션 페이지를 MJPEG(multipart/x-mixed-replace)로 실시간 전송합니다.
+// <img src="/screencast?fps=5&quality=60"> 처럼 쓰며, 연결이 끊기면 시청자에서 빠집니다.
+func Screencast(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "스트리밍을 지원하지 않는 환경입니다.", http.StatusInternalServerError)
+		return
+	}
+
+	fps := queryInt(r, "fps", screencastDefaultFPS, 1, screencastMaxFPS)
+	quality := queryInt(r, "quality", screencastDefaultQuality, 10, 100)
+
+	cast, err := session.screencast()
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+
+	viewer, err := cast.join(fps, quality)
+	if err != nil {
+		log.Printf("화면 전송 시작 실패: %v", err)
+		http.Error(w, "실시간 화면을 시작하지 못했습니다.", http.StatusInternalServerError)
+		return
+	}
+	defer cast.leave(viewer)
+
+	w.Header().Set("Content-Type", "multipart/x-mixed-replace; boundary="+screencastBoundary)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	ctx := r.Context()
+	for {
+		select {
+		case frame, ok := <-viewer.frames:
+			if !ok {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "--%s\r\nContent-Type: image/jpeg\r\nContent-Length: %d\r\n\r\n", screencastBoundary, len(frame)); err != nil {
+				return
+			}
+			if _, err := w.Write(frame); err != nil {
+				return
+			}
+			if _, err := w.Write([]byte("\r\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
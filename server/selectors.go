@@ -0,0 +1,240 @@
+package server
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 기본 선택자 설정. SELECTORS_FILE 환경 변수로 외부 파일을 지정하면 그 파일을 우선 사용하고,
+// 파일이 바뀌면 SELECTORS_WATCH_INTERVAL 마다 확인해 다시 불러옵니다.
+//
+//go:embed config/selectors.json
+var defaultSelectorsJSON []byte
+
+// siteSelectors 는 사이트 하나의 CSS 선택자와 화면에서 찾는 문구입니다.
+type siteSelectors struct {
+	Selectors map[string]string `json:"selectors"`
+	Texts     map[string]string `json:"texts"`
+}
+
+// selectorConfig 는 선택자 설정 파일 전체입니다. 사이트 어댑터 ID 별로 나뉩니다.
+type selectorConfig struct {
+	Version string                   `json:"version"`
+	Sites   map[string]siteSelectors `json:"sites"`
+}
+
+// selectorStatus 는 현재 적용된 설정과 마지막으로 실패한 불러오기입니다.
+// 잘못된 파일은 적용하지 않으므로 LastError 가 있어도 Version 의 설정이 계속 쓰입니다.
+type selectorStatus struct {
+	Version     string    `json:"version"`
+	Source      string    `json:"source"`
+	LoadedAt    time.Time `json:"loadedAt"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt,omitzero"`
+}
+
+var (
+	selectorMu     sync.RWMutex
+	selectorConf   selectorConfig
+	selectorState  selectorStatus
+	selectorSource = strings.TrimSpace(os.Getenv("SELECTORS_FILE"))
+	// 마지막으로 읽은 파일의 수정 시각. 바뀌었을 때만 다시 불러옵니다.
+	selectorModTime time.Time
+)
+
+// loadSelectors 는 선택자 설정을 불러와 적용합니다. 실패하면 기존 설정을 그대로 둡니다.
+func loadSelectors() error {
+	data := defaultSelectorsJSON
+	source := "기본 설정"
+	var modTime time.Time
+
+	if selectorSource != "" {
+		info, err := os.Stat(selectorSource)
+		if err != nil {
+			return recordSelectorError(fmt.Errorf("선택자 파일 확인 실패 (%s): %w", selectorSource, err))
+		}
+		b, err := os.ReadFile(selectorSource)
+		if err != nil {
+			return recordSelectorError(fmt.Errorf("선택자 파일 읽기 실패 (%s): %w", selectorSource, err))
+		}
+		data = b
+		source = selectorSource
+		modTime = info.ModTime()
+	}
+
+	cfg, err := parseSelectors(data)
+	if err != nil {
+		selectorMu.Lock()
+		// 같은 잘못된 파일을 계속 다시 읽지 않도록 수정 시각은 기억합니다.
+		selectorModTime = modTime
+		selectorMu.Unlock()
+		return recordSelectorError(fmt.Errorf("선택자 설정 검증 실패 (%s): %w", source, err))
+	}
+
+	selectorMu.Lock()
+	selectorConf = cfg
+	selectorModTime = modTime
+	selectorState = selectorStatus{
+		Version:  cfg.Version,
+		Source:   source,
+		LoadedAt: time.Now(),
+	}
+	selectorMu.Unlock()
+
+	log.Printf("선택자 설정 %s을(를) 불러왔습니다. (%s)", cfg.Version, source)
+	return nil
+}
+
+func recordSelectorError(err error) error {
+	selectorMu.Lock()
+	selectorState.LastError = err.Error()
+	selectorState.LastErrorAt = time.Now()
+	selectorMu.Unlock()
+	return err
+}
+
+// parseSelectors 는 설정을 읽고, 등록된 사이트 어댑터가 쓰는 키가 모두 있는지 확인합니다.
+func parseSelectors(data []byte) (selectorConfig, error) {
+	var cfg selectorConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return selectorConfig{}, err
+	}
+
+	cfg.Version = strings.TrimSpace(cfg.Version)
+	if cfg.Version == "" {
+		return selectorConfig{}, fmt.Errorf("version이 없습니다")
+	}
+
+	for _, site := range sites {
+		entry, ok := cfg.Sites[site.ID()]
+		if !ok {
+			return selectorConfig{}, fmt.Errorf("사이트 %q의 설정이 없습니다", site.ID())
+		}
+
+		selectors, texts := site.SelectorKeys()
+		var missing []string
+		for _, key := range selectors {
+			if strings.TrimSpace(entry.Selectors[key]) == "" {
+				missing = append(missing, "selectors."+key)
+			}
+		}
+		for _, key := range texts {
+			if strings.TrimSpace(entry.Texts[key]) == "" {
+				missing = append(missing, "texts."+key)
+			}
+		}
+		if len(missing) > 0 {
+			return selectorConfig{}, fmt.Errorf("사이트 %q에 %s 값이 없습니다", site.ID(), strings.Join(missing, ", "))
+		}
+	}
+
+	return cfg, nil
+}
+
+// siteSelector 는 사이트의 CSS 선택자입니다. 설정에 없으면 빈 문자열입니다.
+func siteSelector(siteID, key string) string {
+	selectorMu.RLock()
+	defer selectorMu.RUnlock()
+	return strings.TrimSpace(selectorConf.Sites[siteID].Selectors[key])
+}
+
+// siteText 는 사이트 화면에서 찾는 문구입니다. 설정에 없으면 빈 문자열입니다.
+func siteText(siteID, key string) string {
+	selectorMu.RLock()
+	defer selectorMu.RUnlock()
+	return strings.TrimSpace(selectorConf.Sites[siteID].Texts[key])
+}
+
+func currentSelectors() (selectorConfig, selectorStatus) {
+	selectorMu.RLock()
+	defer selectorMu.RUnlock()
+	return selectorConf, selectorState
+}
+
+// watchSelectors 는 SELECTORS_FILE 의 수정 시각을 주기적으로 확인해 바뀌면 다시 불러옵니다.
+func watchSelectors() {
+	if selectorSource == "" {
+		return
+	}
+
+	interval := durationFromEnv("SELECTORS_WATCH_INTERVAL", 5*time.Second)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := reloadSelectorsIfChanged(); err != nil {
+			log.Printf("선택자 설정을 다시 불러오지 못해 기존 설정을 유지합니다: %v", err)
+		}
+	}
+}
+
+// reloadSelectorsIfChanged 는 파일의 수정 시각이 마지막으로 읽은 때와 다를 때만 다시 불러옵니다.
+// 파일이 잠시 없어진 경우는 편집 중일 수 있으므로 오류로 보지 않습니다.
+func reloadSelectorsIfChanged() error {
+	info, err := os.Stat(selectorSource)
+	if err != nil {
+		return nil
+	}
+
+	selectorMu.RLock()
+	changed := !info.ModTime().Equal(selectorModTime)
+	selectorMu.RUnlock()
+	if !changed {
+		return nil
+	}
+	return loadSelectors()
+}
+
+// requireAdmin 은 ADMIN_TOKEN 이 설정되어 있으면 X-Admin-Token 헤더가 같은지 확인합니다.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		return true
+	}
+	if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Admin-Token")), []byte(token)) == 1 {
+		return true
+	}
+	http.Error(w, "관리자 토큰이 필요합니다.", http.StatusForbidden)
+	return false
+}
+
+// Selectors 는 적용 중인 선택자 설정을 돌려줍니다(GET). POST 는 설정을 바로 다시 불러옵니다.
+func Selectors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if !requireAdmin(w, r) {
+			return
+		}
+		if err := loadSelectors(); err != nil {
+			log.Printf("선택자 설정 다시 불러오기 실패: %v", err)
+			http.Error(w, err.Error()+" (기존 설정을 유지합니다)", http.StatusUnprocessableEntity)
+			return
+		}
+	default:
+		http.Error(w, "GET, POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg, status := currentSelectors()
+	resp := struct {
+		selectorStatus
+		Config selectorConfig `json:"config"`
+	}{
+		selectorStatus: status,
+		Config:         cfg,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("선택자 설정 응답 인코딩 실패: %v", err)
+	}
+}
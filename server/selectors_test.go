@@ -0,0 +1,173 @@
+package server
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSelectorsJSON 은 기본 설정을 읽어 change 로 고친 뒤 다시 JSON 으로 만듭니다.
+func testSelectorsJSON(t *testing.T, change func(cfg *selectorConfig)) []byte {
+	t.Helper()
+	var cfg selectorConfig
+	if err := json.Unmarshal(defaultSelectorsJSON, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	change(&cfg)
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestParseSelectors(t *testing.T) {
+	site := newAUCHogye().ID()
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"기본 설정", defaultSelectorsJSON, ""},
+		{"JSON 오류", []byte(`{"version":`), "unexpected"},
+		{"version 없음", testSelectorsJSON(t, func(cfg *selectorConfig) { cfg.Version = " " }), "version"},
+		{"사이트 없음", testSelectorsJSON(t, func(cfg *selectorConfig) { delete(cfg.Sites, site) }), site},
+		{"선택자 빠짐", testSelectorsJSON(t, func(cfg *selectorConfig) { delete(cfg.Sites[site].Selectors, "lessonButton") }), "selectors.lessonButton"},
+		{"문구 비어 있음", testSelectorsJSON(t, func(cfg *selectorConfig) { cfg.Sites[site].Texts["apply"] = " " }), "texts.apply"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := parseSelectors(tt.data)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseSelectors: %v", err)
+				}
+				if cfg.Version == "" {
+					t.Error("Version is empty")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// useSelectorFile 은 테스트 동안 SELECTORS_FILE 을 임시 파일로 바꾸고, 끝나면 기존 설정을 되돌립니다.
+func useSelectorFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "selectors.json")
+
+	selectorMu.Lock()
+	prevSource, prevConf, prevState, prevMod := selectorSource, selectorConf, selectorState, selectorModTime
+	selectorSource = path
+	selectorMu.Unlock()
+
+	t.Cleanup(func() {
+		selectorMu.Lock()
+		selectorSource, selectorConf, selectorState, selectorModTime = prevSource, prevConf, prevState, prevMod
+		selectorMu.Unlock()
+	})
+	return path
+}
+
+// writeSelectorFile 은 파일을 쓰고 수정 시각을 at 으로 맞춥니다. 파일 시스템의 시각 단위와 관계없이 바뀐 것으로 보이게 합니다.
+func writeSelectorFile(t *testing.T, path string, data []byte, at time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadSelectorsKeepsLastGood(t *testing.T) {
+	path := useSelectorFile(t)
+	site := newAUCHogye().ID()
+	at := time.Now().Add(-time.Hour)
+
+	good := testSelectorsJSON(t, func(cfg *selectorConfig) {
+		cfg.Version = "test-good"
+		cfg.Sites[site].Selectors["lessonButton"] = "a.good"
+	})
+	writeSelectorFile(t, path, good, at)
+	if err := loadSelectors(); err != nil {
+		t.Fatalf("loadSelectors: %v", err)
+	}
+	if got := siteSelector(site, "lessonButton"); got != "a.good" {
+		t.Fatalf("lessonButton = %q, want a.good", got)
+	}
+
+	// 같은 파일은 다시 읽지 않습니다.
+	if err := reloadSelectorsIfChanged(); err != nil {
+		t.Fatalf("reload unchanged: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"JSON 오류", []byte(`{"version":"test-bad","sites":`)},
+		{"키 빠짐", testSelectorsJSON(t, func(cfg *selectorConfig) {
+			cfg.Version = "test-bad"
+			delete(cfg.Sites[site].Selectors, "waitPage")
+		})},
+		{"사이트 없음", []byte(`{"version":"test-bad","sites":{}}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at = at.Add(time.Minute)
+			writeSelectorFile(t, path, tt.data, at)
+
+			if err := reloadSelectorsIfChanged(); err == nil {
+				t.Fatal("reload succeeded with an invalid file")
+			}
+			cfg, status := currentSelectors()
+			if status.Version != "test-good" || cfg.Version != "test-good" {
+				t.Errorf("Version = %q/%q, want the previous test-good", status.Version, cfg.Version)
+			}
+			if status.LastError == "" || status.LastErrorAt.IsZero() {
+				t.Errorf("status = %+v, want LastError recorded", status)
+			}
+			if got := siteSelector(site, "lessonButton"); got != "a.good" {
+				t.Errorf("lessonButton = %q, want the previous a.good", got)
+			}
+
+			// 잘못된 파일이 그대로면 다시 읽지 않고 오류도 반복하지 않습니다.
+			if err := reloadSelectorsIfChanged(); err != nil {
+				t.Errorf("reload of the same invalid file: %v", err)
+			}
+		})
+	}
+
+	// 파일이 없어져도 기존 설정을 유지합니다. 바로 불러오기를 요청하면 오류를 알려줍니다.
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloadSelectorsIfChanged(); err != nil {
+		t.Errorf("watch with missing file: %v", err)
+	}
+	if err := loadSelectors(); err == nil {
+		t.Error("loadSelectors succeeded with a missing file")
+	}
+	if got := siteSelector(site, "lessonButton"); got != "a.good" {
+		t.Errorf("lessonButton after missing file = %q, want a.good", got)
+	}
+
+	// 올바른 파일로 고치면 다시 적용합니다.
+	at = at.Add(time.Minute)
+	writeSelectorFile(t, path, testSelectorsJSON(t, func(cfg *selectorConfig) { cfg.Version = "test-fixed" }), at)
+	if err := reloadSelectorsIfChanged(); err != nil {
+		t.Fatalf("reload fixed file: %v", err)
+	}
+	if _, status := currentSelectors(); status.Version != "test-fixed" || status.LastError != "" {
+		t.Errorf("status after fix = %+v, want test-fixed without error", status)
+	}
+}
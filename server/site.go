@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// siteAdapter 는 시설(지점)마다 다른 주소와 화면 구성을 감춥니다.
+// 세션은 실행할 때 고른 어댑터 하나로 로그인, 강습 목록 이동, 선택, 신청을 처리합니다.
+type siteAdapter interface {
+	// ID 는 /launch?site= 에 쓰는 이름입니다.
+	ID() string
+	Label() string
+
+	// SelectorKeys 는 선택자 설정(config/selectors.json)에서 이 어댑터가 반드시 쓰는 키입니다.
+	// 설정을 불러올 때 빠진 키가 있으면 적용하지 않습니다.
+	SelectorKeys() (selectors, texts []string)
+
+	// Origin 은 로그인 상태를 저장·복원할 때 스토리지를 읽고 쓰는 출처입니다.
+	Origin() string
+	// MainURL 은 세션을 시작할 때 여는 첫 페이지입니다.
+	MainURL() string
+	// LessonListURL 은 강습 목록(신청) 페이지입니다.
+	LessonListURL() string
+	// RegistrationsURL 은 신청내역 페이지입니다. 빈 문자열이면 메인 페이지의 신청내역 링크를 따라갑니다.
+	RegistrationsURL() string
+	// WaitPageSelector 는 접속 대기열 오버레이 선택자입니다. 대기열이 없는 사이트는 빈 문자열입니다.
+	WaitPageSelector() string
+
+	// OpenLogin 은 메인 페이지에서 아이디/비밀번호를 입력할 화면까지 가는 단계입니다.
+	// 단계마다 대기열을 처리합니다.
+	OpenLogin() []siteStep
+	// SubmitLogin 은 아이디/비밀번호를 입력하고 로그인 버튼을 누르는 단계입니다.
+	SubmitLogin(id, password string) []siteStep
+	// LoginFailed 는 로그인 버튼을 누른 뒤 도착한 주소로 실패 여부를 판단합니다.
+	LoginFailed(url string) bool
+	// IsLoginPage 는 주소가 로그인(통합 로그인 포함) 페이지인지 확인합니다. 작업 중 여기로 가면 로그인이 만료된 것입니다.
+	IsLoginPage(url string) bool
+	// LoggedIn 은 현재 페이지가 로그인된 상태인지 확인합니다.
+	LoggedIn(page *rod.Page) (bool, error)
+
+	// SelectArea, SelectEntrance 는 강습 목록에서 프로필의 구분/과정을 고릅니다. 항목이 없으면 false 입니다.
+	SelectArea(page *rod.Page, profile targetProfile) (bool, error)
+	SelectEntrance(page *rod.Page, profile targetProfile) (bool, error)
+	// ClickLesson 은 프로필의 요일/시간에 맞는 신청 버튼을 누릅니다. 버튼이 없으면 false 입니다.
+	ClickLesson(page *rod.Page, profile targetProfile) (bool, error)
+
+	// Lessons 는 현재 강습 목록 페이지를 읽고, ClickLessonByID 는 그 ID로 신청 버튼을 누릅니다.
+	Lessons(page *rod.Page) ([]lesson, error)
+	ClickLessonByID(page *rod.Page, id string) (bool, error)
+
+	// Canary 는 로그인하지 않고 주요 화면을 돌며 선택자와 항목 문구가 그대로 있는지 점검합니다.
+	Canary(c *canaryRun)
+}
+
+// siteStep 은 상태 메시지와 함께 실행하는 사이트 작업 한 단계입니다.
+type siteStep struct {
+	// Message 는 시작할 때, Done 은 끝났을 때 상태 스트림에 남길 문구입니다. Done 은 비워도 됩니다.
+	Message string
+	Done    string
+	Run     func(page *rod.Page) error
+}
+
+// 사용할 수 있는 사이트 어댑터. 앞에 있는 것이 기본값이며 SITE 환경 변수로 바꿀 수 있습니다.
+var sites = []siteAdapter{
+	newAUCHogye(),
+}
+
+var defaultSite = func() siteAdapter {
+	if id := strings.TrimSpace(os.Getenv("SITE")); id != "" {
+		if s, ok := findSite(id); ok {
+			return s
+		}
+		log.Printf("알 수 없는 SITE %q 대신 %s을(를) 기본 사이트로 사용합니다.", id, sites[0].ID())
+	}
+	return sites[0]
+}()
+
+func findSite(id string) (siteAdapter, bool) {
+	for _, s := range sites {
+		if s.ID() == id {
+			return s, true
+		}
+	}
+	return nil, false
+}
+
+// runSiteSteps 는 어댑터가 정한 단계를 차례로 실행합니다. afterEach 가 있으면 단계마다 호출합니다.
+func (s *userSession) runSiteSteps(page *rod.Page, steps []siteStep, afterEach func()) error {
+	for _, st := range steps {
+		if err := s.step(st.Message, func() error {
+			return st.Run(page)
+		}); err != nil {
+			return err
+		}
+		if st.Done != "" {
+			s.pushInfo(st.Done)
+		}
+		if afterEach != nil {
+			afterEach()
+		}
+	}
+	return nil
+}
+
+// Sites 는 선택할 수 있는 사이트 어댑터 목록을 돌려줍니다.
+func Sites(w http.ResponseWriter, r *http.Request) {
+	type siteInfo struct {
+		ID      string `json:"id"`
+		Label   string `json:"label"`
+		Default bool   `json:"default"`
+	}
+
+	out := make([]siteInfo, 0, len(sites))
+	for _, s := range sites {
+		out = append(out, siteInfo{ID: s.ID(), Label: s.Label(), Default: s.ID() == defaultSite.ID()})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		log.Printf("사이트 목록 응답 인코딩 실패: %v", err)
+	}
+}
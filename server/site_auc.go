@@ -0,0 +1,214 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// aucHogye 는 안양도시공사 호계체육관(auc.or.kr) 어댑터입니다.
+// 로그인은 안양시 통합 로그인(newsso.anyang.go.kr)을 거칩니다.
+//
+// AUC_BASE_URL, AUC_SSO_URL 로 주소를 바꾸면 모의 사이트(squash-helper mock)를 대상으로 연습할 수 있습니다.
+type aucHogye struct {
+	origin        string
+	sso           string
+	registrations string // 사이트 안의 신청내역 경로. 비어 있으면 링크를 따라갑니다.
+}
+
+func newAUCHogye() aucHogye {
+	return aucHogye{
+		origin:        envURL("AUC_BASE_URL", "https://www.auc.or.kr"),
+		sso:           envURL("AUC_SSO_URL", "https://newsso.anyang.go.kr/"),
+		registrations: envURL("AUC_REGISTRATIONS_PATH", ""),
+	}
+}
+
+// envURL 은 환경 변수의 주소를 돌려줍니다. 없으면 def 입니다.
+func envURL(name, def string) string {
+	if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+		return v
+	}
+	return def
+}
+
+func (aucHogye) ID() string    { return "auc-hogye" }
+func (aucHogye) Label() string { return "안양도시공사 호계체육관" }
+
+func (aucHogye) SelectorKeys() (selectors, texts []string) {
+	return []string{"loginEntry", "loginId", "loginPassword", "loginButton", "area", "entrance", "lessonButton", "waitPage"},
+		[]string{"loginButton", "logout", "apply", "registrationsLink", "cancel"}
+}
+
+// sel, text 는 선택자 설정에서 값을 읽습니다. 설정이 다시 불러와지면 다음 호출부터 바뀐 값을 씁니다.
+func (a aucHogye) sel(key string) string  { return siteSelector(a.ID(), key) }
+func (a aucHogye) text(key string) string { return siteText(a.ID(), key) }
+
+func (a aucHogye) Origin() string           { return strings.TrimRight(a.origin, "/") }
+func (a aucHogye) MainURL() string          { return a.Origin() + "/hogye/main/view" }
+func (a aucHogye) LessonListURL() string    { return a.Origin() + "/reservation/program/lesson/list" }
+func (a aucHogye) WaitPageSelector() string { return a.sel("waitPage") }
+
+func (a aucHogye) RegistrationsURL() string {
+	if a.registrations == "" {
+		return ""
+	}
+	return a.Origin() + "/" + strings.TrimLeft(a.registrations, "/")
+}
+
+func (a aucHogye) OpenLogin() []siteStep {
+	return []siteStep{
+		{
+			Message: "로그인 페이지로 이동합니다.",
+			Run: func(page *rod.Page) error {
+				if err := page.Navigate(a.Origin() + "/sign/in/base/user"); err != nil {
+					return fmt.Errorf("로그인 페이지 이동 실패: %w", err)
+				}
+				return waitLoad(page)
+			},
+		},
+		{
+			Message: "통합 로그인 버튼을 클릭합니다.",
+			Run: func(page *rod.Page) error {
+				if err := clickElement(page, a.sel("loginEntry")); err != nil {
+					return err
+				}
+				return waitLoad(page)
+			},
+		},
+	}
+}
+
+func (a aucHogye) SubmitLogin(id, password string) []siteStep {
+	return []siteStep{
+		{
+			Message: "아이디 입력 필드를 찾습니다.",
+			Done:    "아이디 입력을 완료했습니다.",
+			Run: func(page *rod.Page) error {
+				if err := inputText(page, a.sel("loginId"), id); err != nil {
+					return err
+				}
+				return pause(page, 1*time.Second)
+			},
+		},
+		{
+			Message: "비밀번호 입력 필드를 찾습니다.",
+			Done:    "비밀번호 입력을 완료했습니다.",
+			Run: func(page *rod.Page) error {
+				if err := inputText(page, a.sel("loginPassword"), password); err != nil {
+					return err
+				}
+				return pause(page, 1*time.Second)
+			},
+		},
+		{
+			Message: "로그인 버튼을 클릭합니다.",
+			Done:    "로그인 버튼을 클릭했습니다.",
+			Run: func(page *rod.Page) error {
+				return clickByText(page, a.sel("loginButton"), a.text("loginButton"))
+			},
+		},
+	}
+}
+
+// LoginFailed 는 로그인에 실패하면 통합 로그인 페이지에 머무르는 것으로 판단합니다.
+func (a aucHogye) LoginFailed(url string) bool {
+	return strings.HasPrefix(url, a.sso)
+}
+
+func (a aucHogye) IsLoginPage(url string) bool {
+	return strings.HasPrefix(url, a.Origin()+"/sign/in/") || strings.HasPrefix(url, a.sso)
+}
+
+func (a aucHogye) LoggedIn(page *rod.Page) (bool, error) {
+	return isLoggedIn(page, a.text("logout"))
+}
+
+func (a aucHogye) SelectArea(page *rod.Page, profile targetProfile) (bool, error) {
+	return forceSelect(page, a.sel("area"), profile.Area)
+}
+
+func (a aucHogye) SelectEntrance(page *rod.Page, profile targetProfile) (bool, error) {
+	return forceSelect(page, a.sel("entrance"), profile.EntranceType)
+}
+
+func (a aucHogye) ClickLesson(page *rod.Page, profile targetProfile) (bool, error) {
+	return clickLessonTime(page, a.lessonButtons(), profile.DayPattern, profile.TimeRange)
+}
+
+func (a aucHogye) Lessons(page *rod.Page) ([]lesson, error) {
+	return scrapeLessons(page, a.lessonButtons())
+}
+
+func (a aucHogye) ClickLessonByID(page *rod.Page, id string) (bool, error) {
+	return clickLessonByID(page, a.lessonButtons(), id)
+}
+
+func (a aucHogye) lessonButtons() lessonButtonSpec {
+	return lessonButtonSpec{Selector: a.sel("lessonButton"), ApplyText: a.text("apply"), SeatsHeader: a.text("seatsHeader")}
+}
+
+// Canary 는 메인, 로그인, 통합 로그인, 강습 목록 화면을 차례로 열고
+// 프로필의 강습 구분/과정이 선택 상자에 그대로 있는지, 신청 버튼과 대기열 선택자가 맞는지 확인합니다. 입력이나 신청은 하지 않습니다.
+func (a aucHogye) Canary(c *canaryRun) {
+	if !c.visit("메인 페이지", func(page *rod.Page) error {
+		return navigate(page, a.MainURL())
+	}) {
+		return
+	}
+
+	c.session.setDialogStage(dialogStageLogin)
+	if c.visit("로그인 페이지", func(page *rod.Page) error {
+		return navigate(page, a.Origin()+"/sign/in/base/user")
+	}) && c.expectSelector("loginEntry") {
+		if c.visit("통합 로그인", func(page *rod.Page) error {
+			if err := clickElement(page, a.sel("loginEntry")); err != nil {
+				return err
+			}
+			return waitLoad(page)
+		}) {
+			c.expectSelector("loginId")
+			c.expectSelector("loginPassword")
+			c.expectText("loginButton", "loginButton")
+		}
+	}
+	c.session.setDialogStage("")
+
+	if !c.visit("강습 목록", func(page *rod.Page) error {
+		return navigate(page, a.LessonListURL())
+	}) {
+		return
+	}
+	c.expectWaitPage("waitPage")
+	areaOK := c.expectSelector("area")
+	c.expectSelector("entrance")
+	if !areaOK {
+		return
+	}
+
+	areas, entrances := c.targets()
+	for _, area := range areas {
+		if !c.expectOption("area", area) {
+			continue
+		}
+		if !c.visit("강습 목록 ("+area+")", func(page *rod.Page) error {
+			ok, err := a.SelectArea(page, targetProfile{Area: area})
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errNotFound("%q 항목을 선택하지 못했습니다", area)
+			}
+			return waitLoad(page)
+		}) {
+			continue
+		}
+		c.expectSelector("lessonButton")
+		for _, entrance := range entrances[area] {
+			c.expectOption("entrance", entrance)
+		}
+	}
+}
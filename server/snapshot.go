@@ -0,0 +1,265 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// 세션마다 보관할 스냅샷 수
+const snapshotKeep = 5
+
+// snapshotSelect 는 페이지의 <select> 하나와 그 옵션 목록입니다.
+type snapshotSelect struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name"`
+	Selected string           `json:"selected"`
+	Options  []snapshotOption `json:"options"`
+}
+
+type snapshotOption struct {
+	Value    string `json:"value"`
+	Text     string `json:"text"`
+	Selected bool   `json:"selected"`
+	Disabled bool   `json:"disabled"`
+}
+
+// snapshotInfo 는 스냅샷 목록에 보여줄 요약입니다.
+type snapshotInfo struct {
+	ID         string    `json:"id"`
+	Reason     string    `json:"reason"`
+	URL        string    `json:"url"`
+	Title      string    `json:"title"`
+	CapturedAt time.Time `json:"capturedAt"`
+	Size       int       `json:"size"`
+}
+
+// pageSnapshot 은 선택자 실패를 분석하기 위해 저장한 페이지 상태 묶음(zip)입니다.
+type pageSnapshot struct {
+	info   snapshotInfo
+	bundle []byte
+}
+
+// capturePage 는 현재 페이지의 HTML, select 옵션, 신청 버튼(lessonSelector), 스크린샷을 zip 으로 묶습니다.
+// 일부 항목을 읽지 못해도 나머지는 담고, 실패 내용은 errors.txt 에 남깁니다.
+func capturePage(page *rod.Page, reason, lessonSelector string) (*pageSnapshot, error) {
+	now := time.Now()
+	info := snapshotInfo{
+		ID:         now.In(kst).Format("20060102-150405.000"),
+		Reason:     reason,
+		CapturedAt: now,
+	}
+
+	var problems []string
+	note := func(what string, err error) {
+		problems = append(problems, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	if pi, err := page.Info(); err == nil {
+		info.URL = pi.URL
+		info.Title = pi.Title
+	} else {
+		note("페이지 정보", err)
+	}
+
+	html, err := page.HTML()
+	if err != nil {
+		note("HTML", err)
+	}
+
+	var selects []snapshotSelect
+	if res, err := page.Eval(`() => Array.from(document.querySelectorAll("select")).map((s) => ({
+		id: s.id,
+		name: s.name,
+		selected: s.value,
+		options: Array.from(s.options).map((o) => ({
+			value: o.value,
+			text: (o.textContent || "").trim(),
+			selected: o.selected,
+			disabled: o.disabled,
+		})),
+	}))`); err != nil {
+		note("select 목록", err)
+	} else if err := res.Value.Unmarshal(&selects); err != nil {
+		note("select 목록", err)
+	}
+
+	var buttons []lessonButton
+	if lessonSelector != "" {
+		if buttons, err = readLessonButtons(page, lessonSelector, ""); err != nil {
+			note("신청 버튼", err)
+		}
+	}
+
+	screenshot, err := page.Screenshot(true, nil)
+	if err != nil {
+		note("스크린샷", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name string, data []byte) error {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	files := []func() error{
+		func() error { return addJSON("meta.json", info) },
+		func() error { return add("page.html", []byte(html)) },
+		func() error { return addJSON("selects.json", selects) },
+		func() error { return addJSON("regist-buttons.json", buttons) },
+	}
+	if screenshot != nil {
+		files = append(files, func() error { return add("screenshot.png", screenshot) })
+	}
+	if len(problems) > 0 {
+		files = append(files, func() error { return add("errors.txt", []byte(strings.Join(problems, "\n"))) })
+	}
+	for _, write := range files {
+		if err := write(); err != nil {
+			return nil, fmt.Errorf("스냅샷 압축 실패: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("스냅샷 압축 실패: %w", err)
+	}
+
+	if html == "" && screenshot == nil {
+		return nil, fmt.Errorf("페이지를 읽지 못했습니다: %s", strings.Join(problems, "; "))
+	}
+
+	info.Size = buf.Len()
+	return &pageSnapshot{info: info, bundle: buf.Bytes()}, nil
+}
+
+func (s *userSession) addSnapshot(snap *pageSnapshot) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	s.snapshots = append(s.snapshots, snap)
+	if len(s.snapshots) > snapshotKeep {
+		s.snapshots = s.snapshots[len(s.snapshots)-snapshotKeep:]
+	}
+}
+
+func (s *userSession) findSnapshot(id string) (*pageSnapshot, bool) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	for _, snap := range s.snapshots {
+		if snap.info.ID == id {
+			return snap, true
+		}
+	}
+	return nil, false
+}
+
+func (s *userSession) listSnapshots() []snapshotInfo {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	out := make([]snapshotInfo, 0, len(s.snapshots))
+	for i := len(s.snapshots) - 1; i >= 0; i-- {
+		out = append(out, s.snapshots[i].info)
+	}
+	return out
+}
+
+// snapshotOnFailure 는 자동 작업 단계가 실패했을 때 페이지 상태를 저장합니다.
+// 작업 컨텍스트는 이미 끝났을 수 있으므로 세션의 원래 페이지로 따로 제한 시간을 둡니다.
+// 사용자가 직접 취소한 경우는 페이지 문제가 아니므로 저장하지 않습니다.
+func (s *userSession) snapshotOnFailure(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	s.mu.Lock()
+	page := s.page
+	s.mu.Unlock()
+	if page == nil {
+		return
+	}
+
+	timed := page.Timeout(timeouts.Navigate)
+	snap, capErr := capturePage(timed, err.Error(), siteSelector(s.site.ID(), "lessonButton"))
+	timed.CancelTimeout()
+	if capErr != nil {
+		log.Printf("실패 스냅샷 저장 실패: %v", capErr)
+		return
+	}
+	s.addSnapshot(snap)
+	s.pushInfo(fmt.Sprintf("[스냅샷] 실패 당시 화면을 저장했습니다. (%s)", snap.info.ID))
+}
+
+func writeSnapshot(w http.ResponseWriter, snap *pageSnapshot) {
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="snapshot-%s.zip"`, snap.info.ID))
+	w.WriteHeader(http.StatusOK)
+	w.Write(snap.bundle)
+}
+
+// Snapshot 은 현재 페이지를 바로 저장해 내려주거나(id 없음), 저장된 스냅샷을 내려줍니다(?id=).
+func Snapshot(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	if id := strings.TrimSpace(r.URL.Query().Get("id")); id != "" {
+		snap, ok := session.findSnapshot(id)
+		if !ok {
+			http.Error(w, "저장된 스냅샷을 찾지 못했습니다.", http.StatusNotFound)
+			return
+		}
+		writeSnapshot(w, snap)
+		return
+	}
+
+	page, done, err := session.begin(r.Context())
+	if err != nil {
+		session.failStep(w, err)
+		return
+	}
+	defer done()
+
+	snap, err := capturePage(page, "수동 요청", siteSelector(session.site.ID(), "lessonButton"))
+	if err != nil {
+		session.failStep(w, &stepError{Step: "페이지 스냅샷", Err: err})
+		return
+	}
+	session.addSnapshot(snap)
+	session.pushInfo(fmt.Sprintf("[스냅샷] 현재 화면을 저장했습니다. (%s)", snap.info.ID))
+	writeSnapshot(w, snap)
+}
+
+func Snapshots(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(session.listSnapshots()); err != nil {
+		log.Printf("스냅샷 목록 응답 인코딩 실패: %v", err)
+	}
+}
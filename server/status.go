@@ -0,0 +1,194 @@
+package server
+
+import (
+	"log"
+	"os"
+	"strings"
+	"sync"
+)
+
+// 느린 구독자 처리 방식
+const (
+	// 버퍼가 가득 차면 가장 오래된 이벤트를 버리고 새 이벤트를 넣습니다.
+	slowClientDropOldest = "drop-oldest"
+	// 버퍼가 가득 차면 연결을 끊습니다. 브라우저의 EventSource 가 다시 연결하면 최신 상태부터 받습니다.
+	slowClientDisconnect = "disconnect"
+)
+
+// statusOptions 는 상태 스트림 구독자별 버퍼 크기, 느린 구독자 처리 방식, 보관할 기록 수입니다.
+// STATUS_CLIENT_BUFFER, STATUS_SLOW_CLIENT, STATUS_HISTORY 환경 변수로 바꿀 수 있습니다.
+type statusOptions struct {
+	Buffer     int
+	SlowClient string
+	History    int
+}
+
+var statusConfig = loadStatusOptions()
+
+func loadStatusOptions() statusOptions {
+	opts := statusOptions{
+		Buffer:     max(intFromEnv("STATUS_CLIENT_BUFFER", 64), 1),
+		SlowClient: slowClientDropOldest,
+		History:    max(intFromEnv("STATUS_HISTORY", 500), 1),
+	}
+
+	switch value := strings.TrimSpace(os.Getenv("STATUS_SLOW_CLIENT")); value {
+	case "", slowClientDropOldest:
+	case slowClientDisconnect:
+		opts.SlowClient = slowClientDisconnect
+	default:
+		log.Printf("STATUS_SLOW_CLIENT 값 %q을(를) 해석하지 못해 기본값 %s을(를) 사용합니다.", value, slowClientDropOldest)
+	}
+	return opts
+}
+
+// statusSubscriber 는 상태 스트림에 연결된 클라이언트 하나입니다.
+type statusSubscriber struct {
+	ch      chan statusEvent
+	dropped int
+}
+
+// statusBroadcaster 는 한 세션의 상태 이벤트를 연결된 모든 클라이언트에게 나눠 보냅니다.
+type statusBroadcaster struct {
+	opts statusOptions
+
+	mu     sync.Mutex
+	subs   map[*statusSubscriber]struct{}
+	nextID uint64
+	closed bool
+
+	// ring 은 최근 이벤트를 ID 순서로 보관하는 원형 버퍼입니다. head 가 가장 오래된 위치입니다.
+	ring  []statusEvent
+	head  int
+	count int
+}
+
+func newStatusBroadcaster(opts statusOptions) *statusBroadcaster {
+	return &statusBroadcaster{
+		opts: opts,
+		subs: make(map[*statusSubscriber]struct{}),
+		ring: make([]statusEvent, opts.History),
+	}
+}
+
+func (b *statusBroadcaster) record(ev statusEvent) {
+	if b.count < len(b.ring) {
+		b.ring[(b.head+b.count)%len(b.ring)] = ev
+		b.count++
+		return
+	}
+	b.ring[b.head] = ev
+	b.head = (b.head + 1) % len(b.ring)
+}
+
+// since 는 ID 가 after 보다 큰 보관 중인 이벤트를 오래된 순서로 돌려줍니다.
+func (b *statusBroadcaster) since(after uint64) []statusEvent {
+	out := make([]statusEvent, 0, b.count)
+	for i := 0; i < b.count; i++ {
+		ev := b.ring[(b.head+i)%len(b.ring)]
+		if ev.ID > after {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+// history 는 보관 중인 전체 기록의 복사본입니다.
+func (b *statusBroadcaster) history() []statusEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.since(0)
+}
+
+// publish 는 이벤트에 ID 를 붙여 기록하고 모든 구독자에게 보냅니다. 구독자 때문에 막히지 않습니다.
+func (b *statusBroadcaster) publish(ev statusEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.nextID++
+	ev.ID = b.nextID
+	b.record(ev)
+
+	for sub := range b.subs {
+		select {
+		case sub.ch <- ev:
+			continue
+		default:
+		}
+
+		sub.dropped++
+		if b.opts.SlowClient == slowClientDisconnect {
+			delete(b.subs, sub)
+			close(sub.ch)
+			log.Printf("상태 스트림 구독자가 이벤트를 따라오지 못해 연결을 끊습니다.")
+			continue
+		}
+
+		// 가장 오래된 이벤트를 버리고 새 이벤트를 넣습니다. 이 잠금 안에서만 보내므로 자리가 생깁니다.
+		select {
+		case <-sub.ch:
+		default:
+		}
+		sub.ch <- ev
+	}
+}
+
+// subscribe 는 새 구독자를 등록하고, 먼저 보내야 할 기록과 해제 함수를 함께 돌려줍니다.
+// resume 이 true 면 ID 가 after 보다 큰 기록을, 아니면 마지막 상태 하나만 돌려줍니다.
+// 세션이 종료되었거나 느린 구독자로 끊기면 채널이 닫힙니다.
+func (b *statusBroadcaster) subscribe(after uint64, resume bool) (<-chan statusEvent, []statusEvent, func()) {
+	sub := &statusSubscriber{ch: make(chan statusEvent, b.opts.Buffer)}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(sub.ch)
+		return sub.ch, nil, func() {}
+	}
+	b.subs[sub] = struct{}{}
+	var history []statusEvent
+	switch {
+	case resume:
+		// 이전 세션의 ID 처럼 아직 발급되지 않은 ID 라면 보관 중인 기록 전체를 보냅니다.
+		if after > b.nextID {
+			after = 0
+		}
+		history = b.since(after)
+	case b.count > 0:
+		history = append(history, b.ring[(b.head+b.count-1)%len(b.ring)])
+	}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if _, ok := b.subs[sub]; !ok {
+			return
+		}
+		delete(b.subs, sub)
+		close(sub.ch)
+		if sub.dropped > 0 {
+			log.Printf("상태 스트림 구독자가 이벤트 %d건을 놓쳤습니다.", sub.dropped)
+		}
+	}
+	return sub.ch, history, cancel
+}
+
+// close 는 모든 구독자의 채널을 닫고 이후 이벤트를 무시합니다.
+func (b *statusBroadcaster) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for sub := range b.subs {
+		close(sub.ch)
+	}
+	b.subs = nil
+}
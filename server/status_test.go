@@ -0,0 +1,181 @@
+package server
+
+import "testing"
+
+// drain 은 채널에 이미 들어 있는 이벤트를 모두 꺼냅니다. 닫힌 채널이면 closed 가 true 입니다.
+func drain(ch <-chan statusEvent) (events []statusEvent, closed bool) {
+	for {
+		select {
+		case ev, ok := <-ch:
+			if !ok {
+				return events, true
+			}
+			events = append(events, ev)
+		default:
+			return events, false
+		}
+	}
+}
+
+func eventIDs(events []statusEvent) []uint64 {
+	ids := make([]uint64, 0, len(events))
+	for _, ev := range events {
+		ids = append(ids, ev.ID)
+	}
+	return ids
+}
+
+func equalIDs(got []statusEvent, want ...uint64) bool {
+	ids := eventIDs(got)
+	if len(ids) != len(want) {
+		return false
+	}
+	for i := range ids {
+		if ids[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestStatusBroadcasterFanOut(t *testing.T) {
+	b := newStatusBroadcaster(statusOptions{Buffer: 4, SlowClient: slowClientDropOldest, History: 10})
+	a, _, cancelA := b.subscribe(0, false)
+	c, _, cancelC := b.subscribe(0, false)
+	defer cancelC()
+
+	b.publish(statusEvent{Message: "1"})
+	b.publish(statusEvent{Message: "2"})
+
+	for name, ch := range map[string]<-chan statusEvent{"a": a, "c": c} {
+		if got, _ := drain(ch); !equalIDs(got, 1, 2) {
+			t.Errorf("subscriber %s got %v, want [1 2]", name, eventIDs(got))
+		}
+	}
+
+	cancelA()
+	cancelA()
+	b.publish(statusEvent{Message: "3"})
+	if _, closed := drain(a); !closed {
+		t.Error("canceled subscriber channel is not closed")
+	}
+	if got, _ := drain(c); !equalIDs(got, 3) {
+		t.Errorf("remaining subscriber got %v, want [3]", eventIDs(got))
+	}
+}
+
+func TestStatusBroadcasterDropOldest(t *testing.T) {
+	b := newStatusBroadcaster(statusOptions{Buffer: 2, SlowClient: slowClientDropOldest, History: 10})
+	ch, _, cancel := b.subscribe(0, false)
+	defer cancel()
+
+	for i := 0; i < 5; i++ {
+		b.publish(statusEvent{Message: "x"})
+	}
+
+	got, closed := drain(ch)
+	if closed {
+		t.Fatal("drop-oldest subscriber was disconnected")
+	}
+	if !equalIDs(got, 4, 5) {
+		t.Errorf("got %v, want the newest [4 5]", eventIDs(got))
+	}
+}
+
+func TestStatusBroadcasterDisconnectSlowClient(t *testing.T) {
+	b := newStatusBroadcaster(statusOptions{Buffer: 2, SlowClient: slowClientDisconnect, History: 10})
+	slow, _, cancelSlow := b.subscribe(0, false)
+	fast, _, cancelFast := b.subscribe(0, false)
+	defer cancelFast()
+
+	b.publish(statusEvent{Message: "1"})
+	b.publish(statusEvent{Message: "2"})
+	if got, _ := drain(fast); !equalIDs(got, 1, 2) {
+		t.Fatalf("fast subscriber got %v, want [1 2]", eventIDs(got))
+	}
+
+	// slow 는 버퍼(2)가 가득 찬 상태에서 세 번째 이벤트를 받지 못해 끊깁니다.
+	b.publish(statusEvent{Message: "3"})
+
+	got, closed := drain(slow)
+	if !closed {
+		t.Fatal("slow subscriber was not disconnected on a full buffer")
+	}
+	if !equalIDs(got, 1, 2) {
+		t.Errorf("slow subscriber got %v before disconnect, want [1 2]", eventIDs(got))
+	}
+	if got, closed := drain(fast); closed || !equalIDs(got, 3) {
+		t.Errorf("fast subscriber got %v (closed %v), want [3] and open", eventIDs(got), closed)
+	}
+
+	// 이미 끊긴 구독자를 해제해도 채널을 두 번 닫지 않습니다.
+	cancelSlow()
+}
+
+func TestStatusBroadcasterClose(t *testing.T) {
+	b := newStatusBroadcaster(statusOptions{Buffer: 2, SlowClient: slowClientDropOldest, History: 10})
+	ch, _, cancel := b.subscribe(0, false)
+
+	b.close()
+	cancel()
+	b.publish(statusEvent{Message: "ignored"})
+
+	if _, closed := drain(ch); !closed {
+		t.Error("subscriber channel is not closed after close")
+	}
+	late, history, _ := b.subscribe(0, true)
+	if _, closed := drain(late); !closed || len(history) != 0 {
+		t.Errorf("subscribe after close: closed %v history %v, want closed and empty", closed, eventIDs(history))
+	}
+}
+
+func TestStatusBroadcasterReplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		after  uint64
+		resume bool
+		want   []uint64
+	}{
+		{"Last-Event-ID 이후", 6, true, []uint64{7, 8}},
+		{"마지막 이벤트까지 받음", 8, true, []uint64{}},
+		{"기록보다 오래된 ID", 1, true, []uint64{4, 5, 6, 7, 8}},
+		{"처음 연결", 0, true, []uint64{4, 5, 6, 7, 8}},
+		{"이전 세션의 ID", 42, true, []uint64{4, 5, 6, 7, 8}},
+		{"이어받기 없음", 6, false, []uint64{8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newStatusBroadcaster(statusOptions{Buffer: 16, SlowClient: slowClientDropOldest, History: 5})
+			for i := 0; i < 8; i++ {
+				b.publish(statusEvent{Message: "x"})
+			}
+			// 기록은 5개만 보관하므로 4~8 이 남아 있습니다.
+
+			ch, history, cancel := b.subscribe(tt.after, tt.resume)
+			defer cancel()
+
+			if !equalIDs(history, tt.want...) {
+				t.Errorf("history = %v, want %v", eventIDs(history), tt.want)
+			}
+
+			// 기록 이후의 새 이벤트는 채널로 이어서 받습니다.
+			b.publish(statusEvent{Message: "live"})
+			if got, _ := drain(ch); !equalIDs(got, 9) {
+				t.Errorf("live events = %v, want [9]", eventIDs(got))
+			}
+		})
+	}
+}
+
+func TestStatusBroadcasterReplayEmpty(t *testing.T) {
+	b := newStatusBroadcaster(statusOptions{Buffer: 4, SlowClient: slowClientDropOldest, History: 5})
+	_, history, cancel := b.subscribe(0, false)
+	defer cancel()
+	if len(history) != 0 {
+		t.Errorf("history = %v, want empty", eventIDs(history))
+	}
+	if got := b.history(); len(got) != 0 {
+		t.Errorf("history() = %v, want empty", eventIDs(got))
+	}
+}
@@ -0,0 +1,212 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// stepError 는 실패한 자동화 단계와 원인을 함께 담습니다.
+type stepError struct {
+	Step string
+	Err  error
+}
+
+func (e *stepError) Error() string {
+	switch {
+	case errors.Is(e.Err, context.Canceled):
+		return fmt.Sprintf("[%s] 단계가 취소되었습니다.", e.Step)
+	case errors.Is(e.Err, context.DeadlineExceeded):
+		return fmt.Sprintf("[%s] 단계가 제한 시간을 초과했습니다.", e.Step)
+	}
+	return fmt.Sprintf("[%s] 단계에서 실패했습니다: %v", e.Step, e.Err)
+}
+
+func (e *stepError) Unwrap() error {
+	return e.Err
+}
+
+// notFoundError 는 요소나 조건을 찾지 못한 경우로, HTTP 404로 응답합니다.
+type notFoundError struct {
+	msg string
+}
+
+func (e notFoundError) Error() string {
+	return e.msg
+}
+
+func errNotFound(format string, args ...any) error {
+	return notFoundError{msg: fmt.Sprintf(format, args...)}
+}
+
+var errNoPage = errors.New("활성화된 페이지가 없습니다")
+
+func stepStatus(err error) int {
+	var nf notFoundError
+	switch {
+	case errors.As(err, &nf):
+		return http.StatusNotFound
+	case errors.Is(err, errNoPage):
+		return http.StatusBadRequest
+	case errors.Is(err, context.Canceled):
+		return http.StatusConflict
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// step 은 단계 시작을 상태 스트림에 알리고 fn을 실행합니다.
+// 실패하면 어떤 단계에서 실패했는지 담은 stepError를 반환합니다.
+func (s *userSession) step(name string, fn func() error) error {
+	s.pushInfo(name)
+	if err := fn(); err != nil {
+		return &stepError{Step: strings.TrimSuffix(name, "."), Err: err}
+	}
+	return nil
+}
+
+// failStep 은 단계 실패를 로그와 상태 스트림에 남기고 HTTP 오류로 응답합니다.
+func (s *userSession) failStep(w http.ResponseWriter, err error) {
+	log.Printf("자동화 단계 실패: %v", err)
+	s.pushError(err.Error())
+	http.Error(w, err.Error(), stepStatus(err))
+}
+
+// waitLoad, navigate, reload 는 timeouts.Navigate 안에 끝나야 합니다.
+// 제한 시간을 건 페이지는 끝나면 CancelTimeout 으로 타이머를 정리합니다.
+func waitLoad(page *rod.Page) error {
+	timed := page.Timeout(timeouts.Navigate)
+	defer timed.CancelTimeout()
+
+	if err := timed.WaitLoad(); err != nil {
+		return fmt.Errorf("페이지 로딩 대기 실패: %w", err)
+	}
+	return nil
+}
+
+func navigate(page *rod.Page, url string) error {
+	timed := page.Timeout(timeouts.Navigate)
+	err := timed.Navigate(url)
+	timed.CancelTimeout()
+	if err != nil {
+		return fmt.Errorf("%s 이동 실패: %w", url, err)
+	}
+	return waitLoad(page)
+}
+
+func reload(page *rod.Page) error {
+	timed := page.Timeout(timeouts.Navigate)
+	err := timed.Reload()
+	timed.CancelTimeout()
+	if err != nil {
+		return fmt.Errorf("새로고침 실패: %w", err)
+	}
+	return waitLoad(page)
+}
+
+// findElement 는 selector 에 해당하는 요소가 나타날 때까지 최대 timeouts.Element 동안 기다립니다.
+func findElement(page *rod.Page, selector string) (*rod.Element, error) {
+	timed := page.Timeout(timeouts.Element)
+	el, err := timed.Element(selector)
+	if err != nil {
+		timed.CancelTimeout()
+		if page.GetContext().Err() != nil {
+			return nil, err
+		}
+		return nil, errNotFound("%s 요소를 찾지 못했습니다 (%v)", selector, err)
+	}
+	return el.CancelTimeout(), nil
+}
+
+// inputText 는 selector 로 찾은 입력 필드에 값을 입력합니다.
+func inputText(page *rod.Page, selector, value string) error {
+	el, err := findElement(page, selector)
+	if err != nil {
+		return err
+	}
+	if err := el.Input(value); err != nil {
+		return fmt.Errorf("%s 입력 실패: %w", selector, err)
+	}
+	return nil
+}
+
+// clickElement 는 selector 로 찾은 요소를 클릭합니다.
+func clickElement(page *rod.Page, selector string) error {
+	el, err := findElement(page, selector)
+	if err != nil {
+		return err
+	}
+	if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+		return fmt.Errorf("%s 클릭 실패: %w", selector, err)
+	}
+	return nil
+}
+
+// clickByText 는 selector 에 해당하는 요소 중 텍스트가 text 와 같은 첫 요소를 클릭합니다.
+func clickByText(page *rod.Page, selector, text string) error {
+	elements, err := page.Elements(selector)
+	if err != nil {
+		return fmt.Errorf("%s 요소 조회 실패: %w", selector, err)
+	}
+
+	for _, el := range elements {
+		t, err := el.Text()
+		if err != nil || strings.TrimSpace(t) != text {
+			continue
+		}
+		if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return fmt.Errorf("%q 클릭 실패: %w", text, err)
+		}
+		return nil
+	}
+
+	return errNotFound("%q 버튼을 찾지 못했습니다", text)
+}
+
+// selectAndWait 는 choose 로 항목을 고른 뒤 목록 갱신과 대기열을 처리합니다.
+func (s *userSession) selectAndWait(page *rod.Page, what, want string, choose func() (bool, error)) error {
+	ok, err := choose()
+	if err != nil {
+		return fmt.Errorf("%s 선택 실패: %w", what, err)
+	}
+	if !ok {
+		return errNotFound("%s에서 %q 항목을 찾지 못했습니다", what, want)
+	}
+	if err := waitLoad(page); err != nil {
+		return err
+	}
+	s.handleWaitPage(page)
+	return nil
+}
+
+// chooseArea, chooseEntrance 는 사이트 어댑터로 강습 구분/과정을 고르고 목록이 갱신될 때까지 기다립니다.
+func (s *userSession) chooseArea(page *rod.Page, profile targetProfile) error {
+	return s.selectAndWait(page, "강습 구분", profile.Area, func() (bool, error) {
+		return s.site.SelectArea(page, profile)
+	})
+}
+
+func (s *userSession) chooseEntrance(page *rod.Page, profile targetProfile) error {
+	return s.selectAndWait(page, "강습 과정", profile.EntranceType, func() (bool, error) {
+		return s.site.SelectEntrance(page, profile)
+	})
+}
+
+func (s *userSession) selectArea(page *rod.Page, profile targetProfile) error {
+	return s.step("강습 구분을 선택합니다.", func() error {
+		return s.chooseArea(page, profile)
+	})
+}
+
+func (s *userSession) selectEntrance(page *rod.Page, profile targetProfile) error {
+	return s.step("강습 과정을 선택합니다.", func() error {
+		return s.chooseEntrance(page, profile)
+	})
+}
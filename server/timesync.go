@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	siteClockSamples      = 8
+	siteClockResyncPeriod = 10 * time.Minute
+)
+
+// siteClocks 는 사이트 주소(Origin)별 서버 시계입니다. 처음 쓸 때 만들고 그때부터 주기적으로 동기화합니다.
+var (
+	siteClockMu sync.Mutex
+	siteClocks  = map[string]*siteClock{}
+)
+
+// clockFor 는 site 서버 시계를 기준으로 보정된 시각을 제공합니다. 같은 주소를 쓰는 어댑터는 시계를 함께 씁니다.
+func clockFor(site siteAdapter) *siteClock {
+	target := site.Origin() + "/"
+
+	siteClockMu.Lock()
+	defer siteClockMu.Unlock()
+
+	if c, ok := siteClocks[target]; ok {
+		return c
+	}
+
+	c := newSiteClock(target, &http.Client{Timeout: 5 * time.Second})
+	siteClocks[target] = c
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stop = cancel
+	go c.run(ctx)
+	return c
+}
+
+// siteClock 은 HTTP Date 헤더와 왕복 시간으로 대상 서버와의 시계 차이를 추정합니다.
+//
+// Date 헤더는 초 단위로 잘려 있으므로 한 번의 샘플은
+// [Date - 수신시각, Date + 1초 - 송신시각] 범위만 알려줍니다.
+// 여러 샘플의 범위를 교집합으로 좁혀 그 중앙값을 offset으로 사용합니다.
+type siteClock struct {
+	target string
+	client *http.Client
+	// stop 은 주기 동기화를 멈춥니다. clockFor 로 만든 시계에만 있습니다.
+	stop context.CancelFunc
+
+	mu          sync.RWMutex
+	offset      time.Duration
+	uncertainty time.Duration
+	rtt         time.Duration
+	syncedAt    time.Time
+	lastErr     string
+}
+
+type siteClockStatus struct {
+	Target        string    `json:"target"`
+	OffsetMs      int64     `json:"offsetMs"`
+	UncertaintyMs int64     `json:"uncertaintyMs"`
+	RttMs         int64     `json:"rttMs"`
+	SyncedAt      time.Time `json:"syncedAt"`
+	SiteTime      time.Time `json:"siteTime"`
+	LocalTime     time.Time `json:"localTime"`
+	Error         string    `json:"error,omitempty"`
+}
+
+func newSiteClock(target string, client *http.Client) *siteClock {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &siteClock{target: target, client: client}
+}
+
+// Now 는 대상 서버 기준으로 보정된 현재 시각을 반환합니다.
+func (c *siteClock) Now() time.Time {
+	c.mu.RLock()
+	offset := c.offset
+	c.mu.RUnlock()
+	return time.Now().Add(offset)
+}
+
+// Until 은 보정된 시계 기준으로 t까지 남은 시간을 반환합니다.
+func (c *siteClock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+// sleepUntil 은 이 시계 기준으로 지정한 시각까지 대기합니다. 취소되면 false를 반환합니다.
+func (c *siteClock) sleepUntil(ctx context.Context, at time.Time) bool {
+	d := c.Until(at)
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (c *siteClock) Status() siteClockStatus {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	return siteClockStatus{
+		Target:        c.target,
+		OffsetMs:      c.offset.Milliseconds(),
+		UncertaintyMs: c.uncertainty.Milliseconds(),
+		RttMs:         c.rtt.Milliseconds(),
+		SyncedAt:      c.syncedAt,
+		SiteTime:      now.Add(c.offset),
+		LocalTime:     now,
+		Error:         c.lastErr,
+	}
+}
+
+// sample 은 한 번의 요청으로 offset이 존재할 수 있는 범위와 왕복 시간을 구합니다.
+func (c *siteClock) sample(ctx context.Context) (lo, hi, rtt time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.target, nil)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+
+	sent := time.Now()
+	resp, err := c.client.Do(req)
+	received := time.Now()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	resp.Body.Close()
+
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Date 헤더 해석 실패: %w", err)
+	}
+
+	lo = date.Sub(received)
+	hi = date.Add(time.Second).Sub(sent)
+	return lo, hi, received.Sub(sent), nil
+}
+
+// Sync 는 여러 번 샘플링해 offset을 다시 추정합니다.
+func (c *siteClock) Sync(ctx context.Context, samples int) error {
+	if samples <= 0 {
+		samples = siteClockSamples
+	}
+
+	var (
+		lo, hi   time.Duration
+		bestRTT  time.Duration
+		got      int
+		firstErr error
+	)
+
+	for i := 0; i < samples; i++ {
+		if i > 0 {
+			// 초 경계를 고르게 훑도록 샘플 간격을 어긋나게 둡니다.
+			select {
+			case <-time.After(time.Second/time.Duration(samples) + 37*time.Millisecond):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		sLo, sHi, rtt, err := c.sample(ctx)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		if got == 0 {
+			lo, hi, bestRTT = sLo, sHi, rtt
+		} else {
+			lo = max(lo, sLo)
+			hi = min(hi, sHi)
+			bestRTT = min(bestRTT, rtt)
+		}
+		got++
+
+		// 교집합이 비면 네트워크 지연이 튄 것이므로 이번 샘플로 다시 시작합니다.
+		if lo > hi {
+			lo, hi = sLo, sHi
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if got == 0 {
+		if firstErr == nil {
+			firstErr = errors.New("샘플을 얻지 못했습니다")
+		}
+		c.lastErr = firstErr.Error()
+		return firstErr
+	}
+
+	c.offset = (lo + hi) / 2
+	c.uncertainty = (hi - lo) / 2
+	c.rtt = bestRTT
+	c.syncedAt = time.Now()
+	c.lastErr = ""
+	return nil
+}
+
+func (c *siteClock) run(ctx context.Context) {
+	for {
+		syncCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		if err := c.Sync(syncCtx, siteClockSamples); err != nil {
+			if ctx.Err() != nil {
+				cancel()
+				return
+			}
+			log.Printf("사이트 시계 동기화 실패 (%s): %v", c.target, err)
+		} else {
+			st := c.Status()
+			log.Printf("사이트 시계 동기화 완료 (%s): offset=%dms ±%dms rtt=%dms", c.target, st.OffsetMs, st.UncertaintyMs, st.RttMs)
+		}
+		cancel()
+
+		select {
+		case <-time.After(siteClockResyncPeriod):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SiteTime 은 세션 사이트의 시계 상태를 돌려줍니다. 세션이 없으면 ?site= 또는 기본 사이트입니다.
+func SiteTime(w http.ResponseWriter, r *http.Request) {
+	site := defaultSite
+	if _, session, ok := getSessionFromRequest(r); ok && session != nil {
+		site = session.site
+	} else if id := r.URL.Query().Get("site"); id != "" {
+		s, ok := findSite(id)
+		if !ok {
+			http.Error(w, "알 수 없는 사이트입니다.", http.StatusBadRequest)
+			return
+		}
+		site = s
+	}
+	siteTime := clockFor(site)
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := siteTime.Sync(r.Context(), siteClockSamples); err != nil {
+			log.Printf("사이트 시계 동기화 실패: %v", err)
+			http.Error(w, "사이트 시계 동기화에 실패했습니다.", http.StatusBadGateway)
+			return
+		}
+	default:
+		http.Error(w, "GET, POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(siteTime.Status()); err != nil {
+		log.Printf("사이트 시계 응답 인코딩 실패: %v", err)
+	}
+}
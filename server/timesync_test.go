@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// dateServer 는 실제 시각에서 offset 만큼 어긋난 Date 헤더를 내려주는 서버입니다.
+func dateServer(t *testing.T, offset time.Duration) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(offset).UTC().Format(http.TimeFormat))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestSiteClockSync(t *testing.T) {
+	for _, offset := range []time.Duration{0, 3 * time.Second, -90 * time.Second} {
+		t.Run(offset.String(), func(t *testing.T) {
+			srv := dateServer(t, offset)
+			c := newSiteClock(srv.URL+"/", srv.Client())
+
+			if err := c.Sync(context.Background(), 6); err != nil {
+				t.Fatalf("Sync: %v", err)
+			}
+
+			st := c.Status()
+			// 교집합의 중앙값이므로 실제 offset 은 uncertainty 안에 있어야 합니다.
+			slack := 50 * time.Millisecond
+			diff := (time.Duration(st.OffsetMs) * time.Millisecond) - offset
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > time.Duration(st.UncertaintyMs)*time.Millisecond+slack {
+				t.Errorf("offset = %dms ±%dms, want %v", st.OffsetMs, st.UncertaintyMs, offset)
+			}
+			// 초 경계를 훑도록 샘플을 어긋나게 두므로 범위가 1초보다 좁아져야 합니다.
+			if st.UncertaintyMs >= 500 {
+				t.Errorf("uncertainty = %dms, want < 500ms", st.UncertaintyMs)
+			}
+			if st.Error != "" {
+				t.Errorf("Error = %q, want empty", st.Error)
+			}
+
+			until := c.Until(time.Now().Add(offset + time.Minute))
+			if until < time.Minute-time.Second || until > time.Minute+time.Second {
+				t.Errorf("Until = %v, want about 1m", until)
+			}
+		})
+	}
+}
+
+func TestSiteClockSyncWithoutDate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header()["Date"] = nil
+	}))
+	defer srv.Close()
+
+	c := newSiteClock(srv.URL+"/", srv.Client())
+	if err := c.Sync(context.Background(), 2); err == nil {
+		t.Fatal("Sync succeeded without Date header")
+	}
+	if st := c.Status(); !strings.Contains(st.Error, "Date") || st.OffsetMs != 0 {
+		t.Errorf("Status = %+v, want Date error and zero offset", st)
+	}
+}
+
+func TestClockForPerOrigin(t *testing.T) {
+	first := dateServer(t, 0)
+	second := dateServer(t, 0)
+
+	t.Setenv("AUC_BASE_URL", first.URL)
+	a := clockFor(newAUCHogye())
+	if got := clockFor(newAUCHogye()); got != a {
+		t.Error("clockFor returned a different clock for the same origin")
+	}
+
+	t.Setenv("AUC_BASE_URL", second.URL)
+	b := clockFor(newAUCHogye())
+	if b == a || b.target != second.URL+"/" {
+		t.Errorf("clockFor target = %s, want %s/", b.target, second.URL)
+	}
+	a.stop()
+	b.stop()
+}
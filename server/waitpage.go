@@ -0,0 +1,243 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+const (
+	// 대기열 처리 방식: force 는 대기열 오버레이를 바로 제거하고, wait 는 스스로 사라질 때까지 기다립니다.
+	waitModeForce = "force"
+	waitModeWait  = "wait"
+
+	waitPagePollInterval = 1 * time.Second
+	waitPageMaxWait      = 10 * time.Minute
+	// 강제 제거 후 대기열이 다시 나타나는지 확인하는 시간
+	waitPageRecheckDelay = 1500 * time.Millisecond
+)
+
+// waitPageInfo 는 대기열 오버레이에서 읽어온 정보입니다.
+type waitPageInfo struct {
+	Present   bool   `json:"present"`
+	Visible   bool   `json:"visible"`
+	Position  int    `json:"position"`
+	Estimated string `json:"estimated,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+var (
+	waitPositionPattern   = regexp.MustCompile(`(\d[\d,]*)\s*(?:번째|번|명)`)
+	waitClockPattern      = regexp.MustCompile(`\d{1,2}:\d{2}(?::\d{2})?`)
+	waitDurationPattern   = regexp.MustCompile(`(\d+)\s*(시간|분|초)`)
+	waitWhitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+func (i waitPageInfo) summary() string {
+	parts := []string{}
+	if i.Position >= 0 {
+		parts = append(parts, fmt.Sprintf("대기 순번 %d", i.Position))
+	}
+	if i.Estimated != "" {
+		parts = append(parts, "예상 대기 "+i.Estimated)
+	}
+	if len(parts) == 0 {
+		return "대기 정보 없음"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseWaitPageText 는 대기열 안내 문구에서 순번과 예상 대기 시간을 추출합니다.
+func parseWaitPageText(text string) (int, string) {
+	position := -1
+	if m := waitPositionPattern.FindStringSubmatch(text); m != nil {
+		if n, err := strconv.Atoi(strings.ReplaceAll(m[1], ",", "")); err == nil {
+			position = n
+		}
+	}
+
+	estimated := waitClockPattern.FindString(text)
+	if estimated == "" {
+		var parts []string
+		for _, m := range waitDurationPattern.FindAllStringSubmatch(text, -1) {
+			parts = append(parts, m[1]+m[2])
+		}
+		estimated = strings.Join(parts, " ")
+	}
+
+	return position, estimated
+}
+
+// readWaitPage 는 대기열 오버레이(selector)가 있는지 최대 timeout 동안 확인하고 내용을 읽습니다.
+// timeout 이 0이면 기다리지 않고 현재 상태만 확인합니다. selector 가 비어 있으면 대기열이 없는 사이트입니다.
+func readWaitPage(page *rod.Page, selector string, timeout time.Duration) waitPageInfo {
+	info := waitPageInfo{Position: -1}
+	if selector == "" {
+		return info
+	}
+
+	var el *rod.Element
+	if timeout > 0 {
+		timed := page.Timeout(timeout)
+		if found, err := timed.Element(selector); err == nil {
+			el = found.CancelTimeout()
+		} else {
+			timed.CancelTimeout()
+		}
+	} else {
+		el, _ = page.Sleeper(rod.NotFoundSleeper).Element(selector)
+	}
+	if el == nil {
+		return info
+	}
+	info.Present = true
+
+	res, err := el.Eval(`() => {
+		const style = window.getComputedStyle(this);
+		return {
+			visible: style.display !== "none" && style.visibility !== "hidden",
+			text: (this.innerText || "").trim(),
+		};
+	}`)
+	if err != nil {
+		return info
+	}
+
+	var raw struct {
+		Visible bool   `json:"visible"`
+		Text    string `json:"text"`
+	}
+	if err := res.Value.Unmarshal(&raw); err != nil {
+		return info
+	}
+
+	info.Visible = raw.Visible
+	info.Text = waitWhitespacePattern.ReplaceAllString(raw.Text, " ")
+	info.Position, info.Estimated = parseWaitPageText(info.Text)
+	return info
+}
+
+func (s *userSession) getWaitMode() string {
+	s.settingsMu.Lock()
+	defer s.settingsMu.Unlock()
+
+	if s.waitMode == "" {
+		return waitModeForce
+	}
+	return s.waitMode
+}
+
+func (s *userSession) setWaitMode(mode string) {
+	s.settingsMu.Lock()
+	s.waitMode = mode
+	s.settingsMu.Unlock()
+}
+
+// handleWaitPage 는 대기열을 감지해 상태를 알리고, 세션의 처리 방식에 따라 제거하거나 기다립니다.
+func (s *userSession) handleWaitPage(page *rod.Page) {
+	s.handleWaitPageWithMode(page, s.getWaitMode())
+}
+
+func (s *userSession) handleWaitPageWithMode(page *rod.Page, mode string) {
+	info := readWaitPage(page, s.site.WaitPageSelector(), 1*time.Second)
+	if !info.Present {
+		return
+	}
+
+	s.pushStatus("queue", "대기열 감지: "+info.summary())
+
+	if mode == waitModeWait {
+		s.waitForQueue(page, info)
+		return
+	}
+
+	removeWaitPage(page, s.site.WaitPageSelector())
+
+	if err := pause(page, waitPageRecheckDelay); err != nil {
+		return
+	}
+	if again := readWaitPage(page, s.site.WaitPageSelector(), 0); again.Present && again.Visible {
+		s.pushStatus("queue", "대기열을 제거했지만 다시 나타났습니다. 실제 대기열일 수 있습니다: "+again.summary())
+		return
+	}
+	s.pushInfo("대기열 오버레이를 제거했습니다.")
+}
+
+// waitForQueue 는 대기열이 스스로 사라질 때까지 순번 변화를 알리며 기다립니다.
+func (s *userSession) waitForQueue(page *rod.Page, info waitPageInfo) {
+	start := time.Now()
+	last := info
+
+	for time.Since(start) < waitPageMaxWait {
+		if err := pause(page, waitPagePollInterval); err != nil {
+			s.pushError("대기열 대기가 중단되었습니다.")
+			return
+		}
+
+		cur := readWaitPage(page, s.site.WaitPageSelector(), 0)
+		if !cur.Present || !cur.Visible {
+			s.pushInfo(fmt.Sprintf("대기열이 해소되었습니다. (%s 대기)", time.Since(start).Round(time.Second)))
+			return
+		}
+
+		if cur.Position != last.Position || cur.Estimated != last.Estimated {
+			s.pushStatus("queue", "대기 중: "+cur.summary())
+			last = cur
+		}
+	}
+
+	s.pushError(fmt.Sprintf("대기열이 %s 안에 해소되지 않았습니다.", waitPageMaxWait))
+}
+
+func Waiting(w http.ResponseWriter, r *http.Request) {
+	session, _, ok := requireSession(w, r)
+	if !ok {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		mode := r.URL.Query().Get("mode")
+		if mode != waitModeForce && mode != waitModeWait {
+			http.Error(w, "mode는 force 또는 wait 이어야 합니다.", http.StatusBadRequest)
+			return
+		}
+		session.setWaitMode(mode)
+		session.pushInfo(fmt.Sprintf("대기열 처리 방식을 %s(으)로 변경했습니다.", mode))
+	default:
+		http.Error(w, "GET, POST 메서드만 허용됩니다.", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 다른 작업이 페이지를 쓰는 중이면 오래 기다리지 않고 대기열 정보 없이 응답합니다.
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	info := waitPageInfo{Position: -1}
+	if page, done, err := session.begin(ctx); err == nil {
+		info = readWaitPage(page, session.site.WaitPageSelector(), 0)
+		done()
+	}
+
+	resp := struct {
+		Mode string       `json:"mode"`
+		Info waitPageInfo `json:"info"`
+	}{
+		Mode: session.getWaitMode(),
+		Info: info,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("대기열 상태 응답 인코딩 실패: %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package server
+
+import "testing"
+
+func TestParseWaitPageText(t *testing.T) {
+	tests := []struct {
+		text      string
+		position  int
+		estimated string
+	}{
+		{"현재 대기 순번은 1,234번째 입니다. 예상 대기 시간 3분 20초", 1234, "3분 20초"},
+		{"고객님 앞에 57명이 대기 중입니다.", 57, ""},
+		{"대기번호 12번 / 예상 입장 시각 10:03:15", 12, "10:03:15"},
+		{"예상 대기 시간 1시간 5분", -1, "1시간 5분"},
+		{"잠시만 기다려 주세요.", -1, ""},
+		{"", -1, ""},
+	}
+
+	for _, tt := range tests {
+		position, estimated := parseWaitPageText(tt.text)
+		if position != tt.position || estimated != tt.estimated {
+			t.Errorf("parseWaitPageText(%q) = %d, %q; want %d, %q", tt.text, position, estimated, tt.position, tt.estimated)
+		}
+	}
+}
+
+func TestWaitPageInfoSummary(t *testing.T) {
+	tests := []struct {
+		info waitPageInfo
+		want string
+	}{
+		{waitPageInfo{Position: 3, Estimated: "1분"}, "대기 순번 3, 예상 대기 1분"},
+		{waitPageInfo{Position: 0}, "대기 순번 0"},
+		{waitPageInfo{Position: -1, Estimated: "10:00"}, "예상 대기 10:00"},
+		{waitPageInfo{Position: -1}, "대기 정보 없음"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.summary(); got != tt.want {
+			t.Errorf("summary(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
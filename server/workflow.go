@@ -0,0 +1,387 @@
+package server
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// 기본 작업 흐름. WORKFLOWS_FILE 환경 변수로 외부 파일을 지정하면 그 파일을 우선 사용합니다.
+//
+//go:embed config/workflows.json
+var defaultWorkflowsJSON []byte
+
+// 작업 흐름 단계 종류
+const (
+	stepNavigate    = "navigate"
+	stepSelect      = "select"
+	stepClickLesson = "click-lesson"
+	stepWaitLoad    = "wait-load"
+	stepRemoveWait  = "remove-wait"
+	stepSleep       = "sleep"
+	stepAssertURL   = "assert-url"
+)
+
+// workflowStep 은 작업 흐름의 한 단계입니다. Type 에 따라 쓰는 항목이 다릅니다.
+//
+//   - navigate: URL 은 main, lessonList, "/"로 시작하는 사이트 경로, 또는 http(s) 주소
+//   - select: Field 는 area(강습 구분) 또는 entrance(강습 과정)이며 값은 프로필에서 가져옵니다
+//   - click-lesson: 프로필의 요일/시간에 맞는 신청 버튼을 누릅니다. 흐름이 끝나면 신청 결과를 판단합니다
+//   - wait-load: 페이지 로딩을 기다립니다
+//   - remove-wait: 대기열을 처리합니다. Mode 가 없으면 세션의 처리 방식을 따릅니다
+//   - sleep: Duration 만큼 기다립니다 (예: "500ms")
+//   - assert-url: 현재 주소가 Match 정규식에 맞는지 확인합니다
+type workflowStep struct {
+	Type     string `json:"type"`
+	Label    string `json:"label,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+	Duration string `json:"duration,omitempty"`
+	Match    string `json:"match,omitempty"`
+
+	sleep   time.Duration
+	pattern *regexp.Regexp
+}
+
+// workflow 는 /action?step= 으로 이름을 지정해 실행하는 단계 묶음입니다.
+// Site 가 있으면 그 사이트 어댑터로 실행한 세션에서만 쓸 수 있습니다.
+type workflow struct {
+	Name  string         `json:"name"`
+	Label string         `json:"label"`
+	Site  string         `json:"site,omitempty"`
+	Done  string         `json:"done,omitempty"`
+	Steps []workflowStep `json:"steps"`
+}
+
+type workflowConfig struct {
+	Workflows []workflow `json:"workflows"`
+}
+
+var (
+	workflowMu sync.RWMutex
+	workflows  []workflow
+)
+
+func loadWorkflows() error {
+	data := defaultWorkflowsJSON
+	source := "기본 설정"
+
+	if path := strings.TrimSpace(os.Getenv("WORKFLOWS_FILE")); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("작업 흐름 파일 읽기 실패 (%s): %w", path, err)
+		}
+		data = b
+		source = path
+	}
+
+	loaded, err := parseWorkflows(data)
+	if err != nil {
+		return fmt.Errorf("작업 흐름 파싱 실패 (%s): %w", source, err)
+	}
+
+	workflowMu.Lock()
+	workflows = loaded
+	workflowMu.Unlock()
+
+	log.Printf("작업 흐름 %d개를 불러왔습니다. (%s)", len(loaded), source)
+	return nil
+}
+
+func parseWorkflows(data []byte) ([]workflow, error) {
+	var cfg workflowConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	for i := range cfg.Workflows {
+		wf := &cfg.Workflows[i]
+		wf.Name = strings.TrimSpace(wf.Name)
+		if wf.Name == "" {
+			return nil, fmt.Errorf("%d번째 작업 흐름에 name이 없습니다", i+1)
+		}
+		if _, exists := seen[wf.Name]; exists {
+			return nil, fmt.Errorf("작업 흐름 이름 %q이(가) 중복되었습니다", wf.Name)
+		}
+		seen[wf.Name] = struct{}{}
+
+		if wf.Label == "" {
+			wf.Label = wf.Name
+		}
+		if wf.Done == "" {
+			wf.Done = wf.Label + " 완료"
+		}
+		if wf.Site != "" {
+			if _, ok := findSite(wf.Site); !ok {
+				return nil, fmt.Errorf("작업 흐름 %q의 site %q은(는) 알 수 없는 사이트입니다", wf.Name, wf.Site)
+			}
+		}
+		if len(wf.Steps) == 0 {
+			return nil, fmt.Errorf("작업 흐름 %q에 단계가 없습니다", wf.Name)
+		}
+		for j := range wf.Steps {
+			if err := wf.Steps[j].validate(); err != nil {
+				return nil, fmt.Errorf("작업 흐름 %q의 %d번째 단계: %w", wf.Name, j+1, err)
+			}
+		}
+	}
+
+	return cfg.Workflows, nil
+}
+
+func (st *workflowStep) validate() error {
+	switch st.Type {
+	case stepNavigate:
+		switch {
+		case st.URL == "main", st.URL == "lessonList", strings.HasPrefix(st.URL, "/"),
+			strings.HasPrefix(st.URL, "http://"), strings.HasPrefix(st.URL, "https://"):
+		default:
+			return fmt.Errorf("navigate의 url은 main, lessonList, /경로, http(s) 주소 중 하나여야 합니다")
+		}
+	case stepSelect:
+		if st.Field != "area" && st.Field != "entrance" {
+			return fmt.Errorf("select의 field는 area 또는 entrance 이어야 합니다")
+		}
+	case stepRemoveWait:
+		if st.Mode != "" && st.Mode != waitModeForce && st.Mode != waitModeWait {
+			return fmt.Errorf("remove-wait의 mode는 force 또는 wait 이어야 합니다")
+		}
+	case stepSleep:
+		d, err := time.ParseDuration(st.Duration)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("sleep의 duration %q을(를) 해석하지 못했습니다", st.Duration)
+		}
+		st.sleep = d
+	case stepAssertURL:
+		if st.Match == "" {
+			return fmt.Errorf("assert-url에 match가 없습니다")
+		}
+		pattern, err := regexp.Compile(st.Match)
+		if err != nil {
+			return fmt.Errorf("assert-url의 match 정규식 오류: %w", err)
+		}
+		st.pattern = pattern
+	case stepClickLesson, stepWaitLoad:
+	default:
+		return fmt.Errorf("알 수 없는 단계 종류 %q", st.Type)
+	}
+	return nil
+}
+
+// describe 는 상태 스트림에 남길 단계 설명입니다.
+func (st workflowStep) describe() string {
+	if st.Label != "" {
+		return st.Label
+	}
+
+	switch st.Type {
+	case stepNavigate:
+		switch st.URL {
+		case "main":
+			return "메인 페이지로 이동합니다."
+		case "lessonList":
+			return "강습 목록 페이지로 이동합니다."
+		}
+		return fmt.Sprintf("%s(으)로 이동합니다.", st.URL)
+	case stepSelect:
+		if st.Field == "area" {
+			return "강습 구분을 선택합니다."
+		}
+		return "강습 과정을 선택합니다."
+	case stepClickLesson:
+		return "조건에 맞는 강습 시간을 찾는 중입니다."
+	case stepWaitLoad:
+		return "페이지 로딩을 기다립니다."
+	case stepRemoveWait:
+		return "대기열을 확인합니다."
+	case stepSleep:
+		return fmt.Sprintf("%s 기다립니다.", st.sleep)
+	case stepAssertURL:
+		return "현재 주소를 확인합니다."
+	}
+	return st.Type
+}
+
+func listWorkflows() []workflow {
+	workflowMu.RLock()
+	defer workflowMu.RUnlock()
+
+	out := make([]workflow, len(workflows))
+	copy(out, workflows)
+	return out
+}
+
+// findSiteWorkflow 는 이름으로 작업 흐름을 찾습니다. site 에서 쓸 수 없는 흐름은 찾지 못한 것으로 봅니다.
+func findSiteWorkflow(name string, site siteAdapter) (workflow, bool) {
+	workflowMu.RLock()
+	defer workflowMu.RUnlock()
+
+	for _, wf := range workflows {
+		if wf.Name == name && (wf.Site == "" || wf.Site == site.ID()) {
+			return wf, true
+		}
+	}
+	return workflow{}, false
+}
+
+// workflowStepReport 는 실행한 단계 하나의 결과입니다.
+type workflowStepReport struct {
+	Index     int    `json:"index"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	OK        bool   `json:"ok"`
+	Error     string `json:"error,omitempty"`
+	ElapsedMs int64  `json:"elapsedMs"`
+}
+
+// workflowReport 는 작업 흐름 실행 결과입니다. 실패하면 Failed 에 멈춘 단계가 있고 뒤의 단계는 Skipped 만큼 실행하지 않았습니다.
+type workflowReport struct {
+	Message  string               `json:"message"`
+	Workflow string               `json:"workflow"`
+	Profile  string               `json:"profile"`
+	OK       bool                 `json:"ok"`
+	Failed   *workflowStepReport  `json:"failed,omitempty"`
+	Skipped  int                  `json:"skipped"`
+	Steps    []workflowStepReport `json:"steps"`
+	Outcome  *applyOutcome        `json:"outcome,omitempty"`
+}
+
+// runWorkflow 는 단계를 차례로 실행하고 첫 실패에서 멈춥니다.
+// click-lesson 단계에서 신청 버튼을 눌렀으면 흐름이 끝나거나 멈춘 뒤 신청 결과를 판단합니다.
+func (s *userSession) runWorkflow(page *rod.Page, wf workflow, profile targetProfile) (workflowReport, error) {
+	report := workflowReport{
+		Workflow: wf.Name,
+		Profile:  profile.Name,
+		Steps:    []workflowStepReport{},
+	}
+
+	var (
+		clicked bool
+		base    outcomeBaseline
+	)
+
+	for i, st := range wf.Steps {
+		name := fmt.Sprintf("%d/%d %s", i+1, len(wf.Steps), st.describe())
+		started := time.Now()
+
+		err := s.step(name, func() error {
+			switch st.Type {
+			case stepNavigate:
+				return navigate(page, s.workflowURL(st.URL))
+			case stepSelect:
+				if st.Field == "area" {
+					return s.chooseArea(page, profile)
+				}
+				return s.chooseEntrance(page, profile)
+			case stepClickLesson:
+				base = captureBaseline(page)
+				ok, err := s.site.ClickLesson(page, profile)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errNotFound("조건에 맞는 강습 시간 버튼을 찾지 못했습니다")
+				}
+				clicked = true
+				return waitLoad(page)
+			case stepWaitLoad:
+				return waitLoad(page)
+			case stepRemoveWait:
+				mode := st.Mode
+				if mode == "" {
+					mode = s.getWaitMode()
+				}
+				s.handleWaitPageWithMode(page, mode)
+				return nil
+			case stepSleep:
+				return pause(page, st.sleep)
+			case stepAssertURL:
+				if url := currentURL(page); !st.pattern.MatchString(url) {
+					return fmt.Errorf("현재 주소 %s 이(가) %s 에 맞지 않습니다", url, st.Match)
+				}
+			}
+			return nil
+		})
+
+		result := workflowStepReport{
+			Index:     i + 1,
+			Type:      st.Type,
+			Name:      st.describe(),
+			OK:        err == nil,
+			ElapsedMs: time.Since(started).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			report.Steps = append(report.Steps, result)
+			report.Failed = &result
+			report.Skipped = len(wf.Steps) - i - 1
+			report.Message = err.Error()
+			// 신청 버튼을 누른 뒤에 멈췄다면 신청은 이미 들어갔을 수 있으므로 결과를 판단해 함께 알려줍니다.
+			if clicked {
+				out := s.detectOutcome(page, base)
+				report.Outcome = &out
+			}
+			return report, err
+		}
+		report.Steps = append(report.Steps, result)
+	}
+
+	report.OK = true
+	report.Message = wf.Done
+	if clicked {
+		out := s.detectOutcome(page, base)
+		report.Outcome = &out
+	}
+	return report, nil
+}
+
+// workflowURL 은 navigate 단계의 url 을 세션 사이트 기준 주소로 바꿉니다.
+func (s *userSession) workflowURL(url string) string {
+	switch {
+	case url == "main":
+		return s.site.MainURL()
+	case url == "lessonList":
+		return s.site.LessonListURL()
+	case strings.HasPrefix(url, "/"):
+		return s.site.Origin() + url
+	}
+	return url
+}
+
+func writeWorkflowReport(w http.ResponseWriter, status int, report workflowReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Printf("작업 흐름 응답 인코딩 실패: %v", err)
+	}
+}
+
+// Workflows 는 세션 사이트에서 쓸 수 있는 작업 흐름 목록을 돌려줍니다. 세션이 없으면 전체 목록입니다.
+func Workflows(w http.ResponseWriter, r *http.Request) {
+	list := listWorkflows()
+	if _, session, ok := getSessionFromRequest(r); ok && session != nil {
+		filtered := list[:0]
+		for _, wf := range list {
+			if wf.Site == "" || wf.Site == session.site.ID() {
+				filtered = append(filtered, wf)
+			}
+		}
+		list = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(list); err != nil {
+		log.Printf("작업 흐름 응답 인코딩 실패: %v", err)
+	}
+}
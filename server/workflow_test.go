@@ -0,0 +1,70 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWorkflows(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string
+	}{
+		{"기본 설정", string(defaultWorkflowsJSON), ""},
+		{"사이트 지정", `{"workflows":[{"name":"a","site":"auc-hogye","steps":[{"type":"wait-load"}]}]}`, ""},
+		{"JSON 오류", `{"workflows":`, "unexpected"},
+		{"이름 없음", `{"workflows":[{"steps":[{"type":"wait-load"}]}]}`, "name"},
+		{"이름 중복", `{"workflows":[{"name":"a","steps":[{"type":"wait-load"}]},{"name":" a ","steps":[{"type":"wait-load"}]}]}`, "중복"},
+		{"알 수 없는 사이트", `{"workflows":[{"name":"a","site":"nowhere","steps":[{"type":"wait-load"}]}]}`, "알 수 없는 사이트"},
+		{"단계 없음", `{"workflows":[{"name":"a","steps":[]}]}`, "단계가 없습니다"},
+		{"알 수 없는 단계", `{"workflows":[{"name":"a","steps":[{"type":"click"}]}]}`, "알 수 없는 단계 종류"},
+		{"잘못된 주소", `{"workflows":[{"name":"a","steps":[{"type":"navigate","url":"lesson"}]}]}`, "navigate"},
+		{"잘못된 field", `{"workflows":[{"name":"a","steps":[{"type":"select","field":"time"}]}]}`, "field"},
+		{"잘못된 mode", `{"workflows":[{"name":"a","steps":[{"type":"remove-wait","mode":"skip"}]}]}`, "mode"},
+		{"잘못된 duration", `{"workflows":[{"name":"a","steps":[{"type":"sleep","duration":"half"}]}]}`, "duration"},
+		{"0 이하 duration", `{"workflows":[{"name":"a","steps":[{"type":"sleep","duration":"-1s"}]}]}`, "duration"},
+		{"match 없음", `{"workflows":[{"name":"a","steps":[{"type":"assert-url"}]}]}`, "match"},
+		{"잘못된 정규식", `{"workflows":[{"name":"a","steps":[{"type":"wait-load"},{"type":"assert-url","match":"("}]}]}`, "2번째 단계"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := parseWorkflows([]byte(tt.json))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseWorkflows: %v", err)
+				}
+				if len(list) == 0 {
+					t.Error("no workflows")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseWorkflowsDefaults(t *testing.T) {
+	list, err := parseWorkflows([]byte(`{"workflows":[{"name":" quick ","steps":[
+		{"type":"sleep","duration":"250ms"},
+		{"type":"assert-url","match":"/lesson/list$"}
+	]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wf := list[0]
+	if wf.Name != "quick" || wf.Label != "quick" || wf.Done != "quick 완료" {
+		t.Errorf("workflow = %q/%q/%q, want trimmed name with default label and done", wf.Name, wf.Label, wf.Done)
+	}
+	if wf.Steps[0].sleep != 250*time.Millisecond {
+		t.Errorf("sleep = %s, want 250ms", wf.Steps[0].sleep)
+	}
+	if p := wf.Steps[1].pattern; p == nil || !p.MatchString("https://www.auc.or.kr/reservation/program/lesson/list") {
+		t.Errorf("pattern = %v, want compiled match", p)
+	}
+}